@@ -0,0 +1,240 @@
+package twfxr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBusinessHoursTTL = time.Minute
+	defaultOffHoursTTL      = 30 * time.Minute
+)
+
+// CachedFile is a rate file downloaded from the bank.
+type CachedFile struct {
+	// Filename is the filename in the Content-Disposition header, e.g. ExchangeRate@202108290526.csv.
+	Filename string
+	// Data is the raw CSV file.
+	Data []byte
+	// ExpiresAt is when the file may have been updated by the bank. The zero value means the file never changes,
+	// e.g. the rates of a past date.
+	ExpiresAt time.Time
+	// ETag and LastModified are the validators sent by the bank, which are used to revalidate the expired file with
+	// a conditional request.
+	ETag         string
+	LastModified string
+	// FetchedAt is when the file was downloaded or last revalidated.
+	FetchedAt time.Time
+	// Header is the headers of the response kept in Metadata.
+	Header http.Header
+}
+
+// Fresh reports whether the file is still up to date at the given time.
+func (f CachedFile) Fresh(now time.Time) bool {
+	return f.ExpiresAt.IsZero() || now.Before(f.ExpiresAt)
+}
+
+// Cache stores the downloaded rate files keyed by their source URLs. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the file stored with the key and whether it exists.
+	Get(key string) (CachedFile, bool, error)
+	// Set stores the file with the key, replacing the existing one.
+	Set(key string, file CachedFile) error
+}
+
+// WithCache sets the cache of the downloaded rate files. The files of past dates are cached forever, while the files
+// of the current day are cached with the TTLs configured by WithCacheTTL.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithCacheTTL sets how long the files of the current day are cached during and outside of Taiwan business hours,
+// which default to 1 minute and 30 minutes respectively as the bank revises the board rates several times a day
+// during business hours but rarely outside of them.
+func WithCacheTTL(businessHours, offHours time.Duration) Option {
+	return func(c *Client) {
+		c.businessHoursTTL = businessHours
+		c.offHoursTTL = offHours
+	}
+}
+
+// expiresAt returns when a file downloaded at the given time expires. Historical files never expire.
+func (c *Client) expiresAt(now time.Time, historical bool) time.Time {
+	switch {
+	case historical:
+		return time.Time{}
+	case isBusinessHours(now):
+		return now.Add(c.businessHoursTTL)
+	default:
+		return now.Add(c.offHoursTTL)
+	}
+}
+
+// isBusinessHours reports whether the bank is open at the given time, i.e. 09:00 to 16:00 on weekdays in Taiwan.
+func isBusinessHours(t time.Time) bool {
+	t = t.In(asiaTaipei)
+
+	if weekday := t.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+
+	return t.Hour() >= 9 && t.Hour() < 16
+}
+
+// MemoryCache is a Cache in memory.
+type MemoryCache struct {
+	mu    sync.RWMutex
+	files map[string]CachedFile
+}
+
+// NewMemoryCache returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{files: make(map[string]CachedFile)}
+}
+
+func (m *MemoryCache) Get(key string) (CachedFile, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, ok := m.files[key]
+
+	return file, ok, nil
+}
+
+func (m *MemoryCache) Set(key string, file CachedFile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.files[key] = file
+
+	return nil
+}
+
+// FileCache is a Cache on the filesystem. Each file is stored as the raw CSV file along with a JSON file of its
+// metadata, both named after the SHA-256 of the key.
+type FileCache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type fileCacheEntry struct {
+	Key          string
+	Filename     string
+	ExpiresAt    time.Time
+	ETag         string      `json:",omitempty"`
+	LastModified string      `json:",omitempty"`
+	FetchedAt    time.Time   `json:",omitempty"`
+	Header       http.Header `json:",omitempty"`
+}
+
+// NewFileCache returns a FileCache storing the files in the given directory, which is created if not exists.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &FileCache{dir: dir}, nil
+}
+
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:]))
+}
+
+func (f *FileCache) Get(key string) (CachedFile, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := f.path(key)
+
+	b, err := ioutil.ReadFile(path + ".json")
+	if errors.Is(err, os.ErrNotExist) {
+		return CachedFile{}, false, nil
+	} else if err != nil {
+		return CachedFile{}, false, err
+	}
+
+	var entry fileCacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return CachedFile{}, false, fmt.Errorf("failed to decode cache entry: %w", err)
+	}
+
+	// Two keys having the same SHA-256 is practically impossible, this is only a sanity check.
+	if entry.Key != key {
+		return CachedFile{}, false, nil
+	}
+
+	data, err := ioutil.ReadFile(path + ".csv")
+	if errors.Is(err, os.ErrNotExist) {
+		return CachedFile{}, false, nil
+	} else if err != nil {
+		return CachedFile{}, false, err
+	}
+
+	return CachedFile{
+		Filename:     entry.Filename,
+		Data:         data,
+		ExpiresAt:    entry.ExpiresAt,
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		FetchedAt:    entry.FetchedAt,
+		Header:       entry.Header,
+	}, true, nil
+}
+
+func (f *FileCache) Set(key string, file CachedFile) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, err := json.Marshal(fileCacheEntry{
+		Key:          key,
+		Filename:     file.Filename,
+		ExpiresAt:    file.ExpiresAt,
+		ETag:         file.ETag,
+		LastModified: file.LastModified,
+		FetchedAt:    file.FetchedAt,
+		Header:       file.Header,
+	})
+	if err != nil {
+		return err
+	}
+
+	path := f.path(key)
+
+	// The metadata is written last so that Get never sees a metadata without its data.
+	if err := writeFileAtomic(path+".csv", file.Data); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path+".json", b)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,117 @@
+package twfxr_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/suite"
+)
+
+type cacheSuite struct {
+	suite.Suite
+
+	transport *httpmock.MockTransport
+}
+
+func (suite *cacheSuite) SetupTest() {
+	suite.transport = httpmock.NewMockTransport()
+	for _, url := range []string{"https://rate.bot.com.tw/xrt/flcsv/0/day", "https://rate.bot.com.tw/xrt/flcsv/0/2021-08-27"} {
+		suite.transport.RegisterResponder(http.MethodGet, url,
+			func(req *http.Request) (*http.Response, error) {
+				resp := newResponse(http.StatusOK, ExchangeRatePage)
+				resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+				return resp, nil
+			},
+		)
+	}
+}
+
+func (suite *cacheSuite) newClient(cache twfxr.Cache, opts ...twfxr.Option) *twfxr.Client {
+	opts = append(opts, twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}), twfxr.WithCache(cache))
+	return twfxr.NewClient(opts...)
+}
+
+func (suite *cacheSuite) TestMemoryCache() {
+	cache := twfxr.NewMemoryCache()
+	client := suite.newClient(cache)
+
+	for i := 0; i < 3; i++ {
+		currencies, metadata, err := client.GetCurrencyExchangeRates(context.Background())
+		suite.NoError(err)
+		suite.Len(currencies, 19)
+		suite.Equal(time.Date(2021, 8, 29, 5, 26, 0, 0, time.FixedZone("UTC+8", 8*60*60)), metadata.QuotedAt)
+	}
+	suite.Equal(1, suite.transport.GetTotalCallCount())
+
+	file, ok, err := cache.Get("https://rate.bot.com.tw/xrt/flcsv/0/day")
+	suite.NoError(err)
+	suite.True(ok)
+	suite.Equal("ExchangeRate@202108290526.csv", file.Filename)
+	suite.Equal(ExchangeRatePage, string(file.Data))
+	suite.False(file.ExpiresAt.IsZero())
+}
+
+func (suite *cacheSuite) TestHistoricalFilesNeverExpire() {
+	cache := twfxr.NewMemoryCache()
+	client := suite.newClient(cache, twfxr.WithCacheTTL(0, 0))
+
+	_, _, err := client.GetCurrencyExchangeRatesOn(context.Background(), time.Date(2021, 8, 27, 0, 0, 0, 0, time.UTC))
+	suite.NoError(err)
+	_, _, err = client.GetCurrencyExchangeRatesOn(context.Background(), time.Date(2021, 8, 27, 0, 0, 0, 0, time.UTC))
+	suite.NoError(err)
+	suite.Equal(1, suite.transport.GetTotalCallCount())
+
+	file, ok, err := cache.Get("https://rate.bot.com.tw/xrt/flcsv/0/2021-08-27")
+	suite.NoError(err)
+	suite.True(ok)
+	suite.True(file.ExpiresAt.IsZero())
+}
+
+func (suite *cacheSuite) TestExpiredFileIsDownloadedAgain() {
+	cache := twfxr.NewMemoryCache()
+	suite.NoError(cache.Set("https://rate.bot.com.tw/xrt/flcsv/0/day", twfxr.CachedFile{
+		Filename:  "ExchangeRate@202108280000.csv",
+		Data:      []byte(ExchangeRatePage),
+		ExpiresAt: time.Now().Add(-time.Second),
+	}))
+
+	_, metadata, err := suite.newClient(cache).GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.Equal(time.Date(2021, 8, 29, 5, 26, 0, 0, time.FixedZone("UTC+8", 8*60*60)), metadata.QuotedAt)
+	suite.Equal(1, suite.transport.GetTotalCallCount())
+}
+
+func (suite *cacheSuite) TestFileCache() {
+	dir := suite.T().TempDir()
+
+	cache, err := twfxr.NewFileCache(dir)
+	suite.NoError(err)
+
+	_, ok, err := cache.Get("https://rate.bot.com.tw/xrt/flcsv/0/day")
+	suite.NoError(err)
+	suite.False(ok)
+
+	_, _, err = suite.newClient(cache).GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+
+	// A new cache of the same directory, e.g. in the next run of a batch job, reuses the downloaded file.
+	cache, err = twfxr.NewFileCache(dir)
+	suite.NoError(err)
+
+	currencies, metadata, err := suite.newClient(cache).GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.Len(currencies, 19)
+	suite.Equal(1, suite.transport.GetTotalCallCount())
+
+	// The metadata of the download is kept along with the file.
+	suite.False(metadata.FetchedAt.IsZero())
+	suite.Equal(`attachment; filename="ExchangeRate@202108290526.csv"`, metadata.Header.Get("Content-Disposition"))
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(cacheSuite))
+}
@@ -0,0 +1,456 @@
+package twfxr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBaseURL = "https://rate.bot.com.tw"
+
+	// csvPathPrefix is followed by the language of the rate file, 0 for Chinese and 1 for English, and the file, e.g.
+	// /xrt/flcsv/0/day.
+	csvPathPrefix = "/xrt/flcsv/"
+
+	dayCSVFile = "day"
+)
+
+// Client fetches the exchange rates published by Bank of Taiwan.
+//
+// The zero value is not usable, use NewClient instead.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+	language   string
+	now        func() time.Time
+	// err is the error of an invalid option, which is returned by every request.
+	err error
+
+	cache            Cache
+	businessHoursTTL time.Duration
+	offHoursTTL      time.Duration
+
+	maxRetries    int
+	retryBaseWait time.Duration
+	retryMaxWait  time.Duration
+	sleep         func(ctx context.Context, d time.Duration) error
+
+	// mu guards the last downloads, which are revalidated by conditional requests.
+	mu        sync.Mutex
+	lastFiles map[string]CachedFile
+	lastParse map[string]parsedSnapshot
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to download the rate files, e.g. to
+// configure timeouts, proxies or a custom transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the URL the rate files are downloaded from, e.g. to point at a
+// local mirror of https://rate.bot.com.tw.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLanguage sets the language of the rate files downloaded, either LanguageZhTW, which is the default, or
+// LanguageEn, parsed by ParseLanguage. The rates are the same in both languages, Metadata.Language tells the language
+// of a file. Every request of the client fails with an unsupported language.
+func WithLanguage(lang string) Option {
+	return func(c *Client) {
+		language, err := ParseLanguage(lang)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.language = language
+	}
+}
+
+// NewClient returns a Client configured by the given options.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		baseURL:    defaultBaseURL,
+		language:   LanguageZhTW,
+		now:        time.Now,
+
+		businessHoursTTL: defaultBusinessHoursTTL,
+		offHoursTTL:      defaultOffHoursTTL,
+
+		maxRetries:    defaultMaxRetries,
+		retryBaseWait: defaultRetryBaseWait,
+		retryMaxWait:  defaultRetryMaxWait,
+		sleep:         sleep,
+
+		lastFiles: make(map[string]CachedFile),
+		lastParse: make(map[string]parsedSnapshot),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) GetCurrencyExchangeRate(ctx context.Context, currency Currency) (CurrencyExchangeRate, Metadata, error) {
+	results, metadata, err := c.GetCurrencyExchangeRates(ctx)
+	if err != nil {
+		return CurrencyExchangeRate{}, metadata, err
+	}
+
+	v, ok := results[currency]
+	if !ok {
+		return CurrencyExchangeRate{}, metadata, fmt.Errorf("no such currency: %w", ErrNotFound)
+	}
+
+	return v, metadata, nil
+}
+
+func (c *Client) GetCurrencyExchangeRates(ctx context.Context) (map[Currency]CurrencyExchangeRate, Metadata, error) {
+	snapshot, err := c.GetSnapshot(ctx)
+	if err != nil {
+		return nil, snapshot.Metadata, err
+	}
+
+	return snapshot.Map(), snapshot.Metadata, nil
+}
+
+// GetSnapshot returns the latest board rates.
+func (c *Client) GetSnapshot(ctx context.Context) (Snapshot, error) {
+	return c.getSnapshot(ctx, c.csvPath(dayCSVFile), false)
+}
+
+// OpenCurrencyExchangeRates returns a RateReader of the latest board rates, which yields the currencies in the order
+// published by the bank. Without a cache the rates are read while they are downloaded. The caller must close the
+// RateReader.
+func (c *Client) OpenCurrencyExchangeRates(ctx context.Context) (*RateReader, Metadata, error) {
+	return c.openExchangeRates(ctx, c.csvPath(dayCSVFile), false)
+}
+
+// GetCurrencyExchangeRatesOn returns the last board rates quoted on the given date. The calendar date of the
+// given time in its own location is used. An error wrapping ErrNotFound is returned when there is no quote on that
+// date, e.g. on weekends and holidays.
+func (c *Client) GetCurrencyExchangeRatesOn(ctx context.Context, date time.Time) (map[Currency]CurrencyExchangeRate, Metadata, error) {
+	snapshot, err := c.GetSnapshotOn(ctx, date)
+	if err != nil {
+		return nil, snapshot.Metadata, err
+	}
+
+	return snapshot.Map(), snapshot.Metadata, nil
+}
+
+// GetSnapshotOn returns the last board rates quoted on the given date. The calendar date of the given time in its own
+// location is used. An error wrapping ErrNotFound is returned when there is no quote on that date, e.g. on weekends
+// and holidays.
+func (c *Client) GetSnapshotOn(ctx context.Context, date time.Time) (Snapshot, error) {
+	day := date.Format("2006-01-02")
+
+	snapshot, err := c.getSnapshot(ctx, c.csvPath(day), c.isPastDate(date))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return snapshot, fmt.Errorf("no quote on %s: %w", day, err)
+		}
+		return snapshot, err
+	}
+
+	if snapshot.Len() == 0 {
+		return Snapshot{Metadata: snapshot.Metadata}, fmt.Errorf("no quote on %s: %w", day, ErrNotFound)
+	}
+
+	return snapshot, nil
+}
+
+// csvPath returns the path of the given rate file in the language of the client, e.g. /xrt/flcsv/1/day for day in
+// English.
+func (c *Client) csvPath(file string) string {
+	return csvPathPrefix + c.languageIndex() + "/" + file
+}
+
+// languageIndex returns the language of the client in the paths of the files, 0 for Chinese and 1 for English.
+func (c *Client) languageIndex() string {
+	if c.language == LanguageEn {
+		return "1"
+	}
+	return "0"
+}
+
+// isPastDate reports whether the calendar date of the given time is before today in Taiwan, whose rate files never
+// change.
+func (c *Client) isPastDate(date time.Time) bool {
+	return truncateToDate(date).Before(truncateToDate(c.now().In(asiaTaipei)))
+}
+
+func (c *Client) getSnapshot(ctx context.Context, path string, historical bool) (Snapshot, error) {
+	url := c.baseURL + path
+
+	file, notModified, err := c.getExchangeRateCSVFile(ctx, path, historical)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot, ok := c.lastSnapshot(url, file)
+	if !notModified || !ok {
+		snapshot, err = ParseSnapshot(bytes.NewReader(file.Data), file.Filename)
+		if err != nil {
+			return snapshot, err
+		}
+
+		if !historical {
+			c.rememberSnapshot(url, file, snapshot)
+		}
+	}
+
+	stampMetadata(&snapshot.Metadata, url, file, notModified)
+
+	return snapshot, nil
+}
+
+// stampMetadata fills in how the file parsed into the metadata was fetched.
+func stampMetadata(metadata *Metadata, url string, file CachedFile, notModified bool) {
+	metadata.NotModified = notModified
+	metadata.SourceURL = url
+	metadata.FetchedAt = file.FetchedAt
+	metadata.Header = file.Header
+}
+
+func (c *Client) openExchangeRates(ctx context.Context, path string, historical bool) (*RateReader, Metadata, error) {
+	if c.err != nil {
+		return nil, Metadata{}, c.err
+	}
+
+	url := c.baseURL + path
+
+	var (
+		file CachedFile
+		body io.ReadCloser
+	)
+
+	if c.cache == nil {
+		resp, err := c.openCSVFile(ctx, url, nil)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+		file = CachedFile{Filename: resp.filename, FetchedAt: c.now(), Header: filterHeader(resp.header)}
+		body = resp.body
+	} else {
+		var err error
+		if file, _, err = c.getExchangeRateCSVFile(ctx, path, historical); err != nil {
+			return nil, Metadata{}, err
+		}
+		body = ioutil.NopCloser(bytes.NewReader(file.Data))
+	}
+
+	r, metadata, err := openRateReader(body, file.Filename)
+	if err != nil {
+		body.Close()
+		return nil, metadata, err
+	}
+
+	stampMetadata(&metadata, url, file, false)
+
+	return r, metadata, nil
+}
+
+// getExchangeRateCSVFile returns the rate file of the given path from the cache if it is still fresh, or downloads it
+// otherwise. A stale file is revalidated with a conditional request, where notModified reports whether the bank
+// responded 304 Not Modified. The files marked as historical are cached forever.
+func (c *Client) getExchangeRateCSVFile(ctx context.Context, path string, historical bool) (file CachedFile, notModified bool, err error) {
+	if c.err != nil {
+		return CachedFile{}, false, c.err
+	}
+
+	url := c.baseURL + path
+
+	last, ok, err := c.lastFile(url)
+	if err != nil {
+		return CachedFile{}, false, err
+	}
+
+	if ok && last.Fresh(c.now()) {
+		return last, false, nil
+	}
+
+	var validators *CachedFile
+	if ok {
+		validators = &last
+	}
+
+	file, notModified, err = c.downloadCSVFile(ctx, url, validators)
+	if err != nil {
+		return CachedFile{}, false, err
+	}
+
+	file.ExpiresAt = c.expiresAt(c.now(), historical)
+	if err := c.storeFile(url, file, historical); err != nil {
+		return CachedFile{}, false, err
+	}
+
+	return file, notModified, nil
+}
+
+// downloadCSVFile downloads the rate file. When the last download is given, it is revalidated by a conditional request
+// and returned with the validators of the response if the bank responds 304 Not Modified.
+func (c *Client) downloadCSVFile(ctx context.Context, url string, last *CachedFile) (file CachedFile, notModified bool, err error) {
+	resp, err := c.openCSVFile(ctx, url, last)
+	if err != nil {
+		return CachedFile{}, false, err
+	}
+
+	if resp.notModified {
+		file = *last
+		file.FetchedAt = c.now()
+
+		// A 304 response carries the headers which would have been sent in a 200 response, e.g. Date and ETag.
+		file.Header = make(http.Header)
+		for key, values := range last.Header {
+			file.Header[key] = values
+		}
+		for key, values := range filterHeader(resp.header) {
+			file.Header[key] = values
+		}
+
+		if etag := resp.header.Get("ETag"); etag != "" {
+			file.ETag = etag
+		}
+		if lastModified := resp.header.Get("Last-Modified"); lastModified != "" {
+			file.LastModified = lastModified
+		}
+
+		return file, true, nil
+	}
+	defer resp.body.Close()
+
+	data, err := ioutil.ReadAll(resp.body)
+	if err != nil {
+		return CachedFile{}, false, err
+	}
+
+	return CachedFile{
+		Filename:     resp.filename,
+		Data:         data,
+		ETag:         resp.header.Get("ETag"),
+		LastModified: resp.header.Get("Last-Modified"),
+		FetchedAt:    c.now(),
+		Header:       filterHeader(resp.header),
+	}, false, nil
+}
+
+// csvResponse is the response of a request of a rate file.
+type csvResponse struct {
+	filename string
+	header   http.Header
+	// body is the rate file, which the caller must close. It is nil when notModified.
+	body        io.ReadCloser
+	notModified bool
+}
+
+// openCSVFile sends the request of the rate file, retrying the failures configured by WithRetry. When the last
+// download is given, the request is conditional on its validators.
+func (c *Client) openCSVFile(ctx context.Context, url string, last *CachedFile) (resp csvResponse, err error) {
+	err = c.retry(ctx, func() error {
+		resp, err = c.tryOpenCSVFile(ctx, url, last)
+		return err
+	})
+
+	return resp, err
+}
+
+func (c *Client) tryOpenCSVFile(ctx context.Context, url string, last *CachedFile) (csvResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return csvResponse{}, err
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	if last != nil {
+		if last.ETag != "" {
+			req.Header.Set("If-None-Match", last.ETag)
+		}
+		if last.LastModified != "" {
+			req.Header.Set("If-Modified-Since", last.LastModified)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return csvResponse{}, err
+		}
+		return csvResponse{}, &retryableError{err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified && last != nil:
+			return csvResponse{header: resp.Header, notModified: true}, nil
+
+		case resp.StatusCode == http.StatusNotFound:
+			return csvResponse{}, fmt.Errorf("%s: %w", req.URL, ErrNotFound)
+
+		case isRetryableStatus(resp.StatusCode):
+			return csvResponse{}, &retryableError{
+				err:        fmt.Errorf("%s: %s: %w", req.URL, resp.Status, ErrUpstreamUnavailable),
+				retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), c.now()),
+			}
+
+		default:
+			return csvResponse{}, fmt.Errorf("%s: unexpected status %s", req.URL, resp.Status)
+		}
+	}
+
+	filename, err := parseContentDisposition(resp.Header)
+	if err != nil {
+		resp.Body.Close()
+		return csvResponse{}, fmt.Errorf("%s: %w", req.URL, err)
+	}
+
+	return csvResponse{filename: filename, header: resp.Header, body: resp.Body}, nil
+}
+
+// parseContentDisposition returns the filename of the rate file in the Content-Disposition header. The bank responds
+// with an HTML page instead of the file, e.g. during maintenance, which is reported as ErrUnexpectedContentType.
+func parseContentDisposition(header http.Header) (string, error) {
+	if mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil && mediaType == "text/html" {
+		return "", fmt.Errorf("%s: %w", mediaType, ErrUnexpectedContentType)
+	}
+
+	contentDisposition := header.Get("Content-Disposition")
+
+	_, params, err := mime.ParseMediaType(contentDisposition)
+	if err != nil || params["filename"] == "" {
+		return "", fmt.Errorf("no filename in Content-Disposition %q: %w", contentDisposition, ErrUnexpectedContentType)
+	}
+
+	return params["filename"], nil
+}
@@ -0,0 +1,137 @@
+package twfxr_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type clientSuite struct {
+	suite.Suite
+
+	transport *httpmock.MockTransport
+	client    *twfxr.Client
+}
+
+func (suite *clientSuite) SetupTest() {
+	suite.transport = httpmock.NewMockTransport()
+	suite.client = twfxr.NewClient(
+		twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}),
+		twfxr.WithBaseURL("http://mirror.local/"),
+		twfxr.WithUserAgent("twfxr-test"),
+	)
+}
+
+func (suite *clientSuite) TestGetCurrencyExchangeRate() {
+	suite.transport.RegisterResponder(http.MethodGet, "http://mirror.local/xrt/flcsv/0/day",
+		func(req *http.Request) (*http.Response, error) {
+			suite.Equal("twfxr-test", req.Header.Get("User-Agent"))
+
+			resp := newResponse(http.StatusOK, ExchangeRatePage)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+
+	exchangeRate, _, err := suite.client.GetCurrencyExchangeRate(context.Background(), twfxr.CurrencyUSD)
+	suite.NoError(err)
+	suite.Equal(twfxr.Rate(27.845), exchangeRate.BuyingSpot)
+	suite.Equal(1, suite.transport.GetTotalCallCount())
+}
+
+func (suite *clientSuite) TestGetCurrencyExchangeRatesOn() {
+	suite.transport.RegisterResponder(http.MethodGet, "http://mirror.local/xrt/flcsv/0/2021-08-27",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, ExchangeRatePage)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108271600.csv"`)
+			return resp, nil
+		},
+	)
+	suite.transport.RegisterResponder(http.MethodGet, "http://mirror.local/xrt/flcsv/0/2021-08-28",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, strings.SplitN(ExchangeRatePage, "\n", 2)[0])
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108280000.csv"`)
+			return resp, nil
+		},
+	)
+	suite.transport.RegisterResponder(http.MethodGet, "http://mirror.local/xrt/flcsv/0/2021-08-29",
+		httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+	currencies, metadata, err := suite.client.GetCurrencyExchangeRatesOn(context.Background(), time.Date(2021, 8, 27, 0, 0, 0, 0, time.UTC))
+	suite.NoError(err)
+	suite.Len(currencies, 19)
+	suite.Equal(time.Date(2021, 8, 27, 16, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60)), metadata.QuotedAt)
+
+	_, _, err = suite.client.GetCurrencyExchangeRatesOn(context.Background(), time.Date(2021, 8, 28, 0, 0, 0, 0, time.UTC))
+	suite.ErrorIs(err, twfxr.ErrNotFound)
+
+	_, _, err = suite.client.GetCurrencyExchangeRatesOn(context.Background(), time.Date(2021, 8, 29, 0, 0, 0, 0, time.UTC))
+	suite.ErrorIs(err, twfxr.ErrNotFound)
+}
+
+func (suite *clientSuite) TestWithLanguage() {
+	suite.transport.RegisterResponder(http.MethodGet, "http://mirror.local/xrt/flcsv/1/day",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, ExchangeRatePageEn)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+
+	for _, lang := range []string{"en", "EN", "en-US"} {
+		client := twfxr.NewClient(
+			twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}),
+			twfxr.WithBaseURL("http://mirror.local/"),
+			twfxr.WithLanguage(lang),
+		)
+
+		_, metadata, err := client.GetCurrencyExchangeRates(context.Background())
+		suite.NoError(err, lang)
+		suite.Equal(twfxr.LanguageEn, metadata.Language, lang)
+	}
+
+	calls := suite.transport.GetTotalCallCount()
+
+	client := twfxr.NewClient(
+		twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}),
+		twfxr.WithBaseURL("http://mirror.local/"),
+		twfxr.WithLanguage("ja"),
+	)
+
+	_, _, err := client.GetCurrencyExchangeRates(context.Background())
+	suite.EqualError(err, "unsupported language ja")
+
+	_, _, err = client.OpenCurrencyExchangeRates(context.Background())
+	suite.Error(err)
+	suite.Equal(calls, suite.transport.GetTotalCallCount())
+}
+
+func TestParseLanguage(t *testing.T) {
+	for input, want := range map[string]string{
+		"zh-TW": twfxr.LanguageZhTW,
+		"ZH-tw": twfxr.LanguageZhTW,
+		"en":    twfxr.LanguageEn,
+		" En ":  twfxr.LanguageEn,
+		"en_GB": twfxr.LanguageEn,
+	} {
+		lang, err := twfxr.ParseLanguage(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, lang, input)
+	}
+
+	for _, input := range []string{"", "ja", "zh-CN", "eng"} {
+		_, err := twfxr.ParseLanguage(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(clientSuite))
+}
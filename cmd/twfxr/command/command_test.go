@@ -0,0 +1,425 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+// newResponse returns a response whose body keeps returning io.EOF once fully read, like the body of a real response.
+// The bodies of httpmock.NewBytesResponse rewind at the end instead.
+func newResponse(status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status),
+		StatusCode:    status,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		Header:        http.Header{},
+		ContentLength: -1,
+	}
+}
+
+type commandSuite struct {
+	suite.Suite
+}
+
+func (suite *commandSuite) SetupSuite() {
+	page, err := os.ReadFile("../../../testdata/ExchangeRate@202108290526.csv")
+	suite.Require().NoError(err)
+
+	httpmock.Activate()
+	httpmock.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/day",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, page)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+
+	gold, err := os.ReadFile("../../../testdata/GoldPassbook@202108271330.csv")
+	suite.Require().NoError(err)
+
+	httpmock.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/gold/csv/0/day",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, gold)
+			resp.Header.Add("Content-Disposition", `attachment; filename="GoldPassbook@202108271330.csv"`)
+			return resp, nil
+		},
+	)
+}
+
+func (suite *commandSuite) TearDownSuite() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *commandSuite) SetupTest() {
+	output, fromFile, language = "", "", twfxr.LanguageZhTW
+	convertSide, convertKind, convertPlaces = "auto", "spot", 2
+	watchInterval, watchExec, watchWebhook, watchAllHours, watchTimeout = time.Minute, "", "", false, 10*time.Second
+}
+
+func (suite *commandSuite) execute(args ...string) (string, error) {
+	return suite.executeContext(context.Background(), args...)
+}
+
+// executeContext runs the command line with ctx. Cobra only passes the context of the root command to a subcommand
+// whose context is still nil, i.e. on its first execution, so the context is set on the subcommand itself.
+func (suite *commandSuite) executeContext(ctx context.Context, args ...string) (string, error) {
+	var buf bytes.Buffer
+
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs(args)
+
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		cmd = rootCmd
+	}
+
+	err = cmd.ExecuteContext(ctx)
+
+	return buf.String(), err
+}
+
+func (suite *commandSuite) TestRatesTable() {
+	out, err := suite.execute("rates", "USD", "zar")
+	suite.NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	suite.Len(lines, 4)
+	suite.Contains(lines[2], "USD")
+	suite.Contains(lines[2], "27.845000")
+	suite.Contains(lines[3], "ZAR")
+	suite.Contains(lines[3], "-")
+}
+
+func (suite *commandSuite) TestRatesTableLanguage() {
+	out, err := suite.execute("rates", "USD")
+	suite.NoError(err)
+	suite.Contains(out, "本行買入:現金")
+	suite.Contains(out, "美金 (USD)")
+
+	out, err = suite.execute("rates", "USD", "--lang", "en")
+	suite.NoError(err)
+	suite.Contains(out, "BUYING: CASH")
+	suite.Contains(out, "US Dollar (USD)")
+	suite.NotContains(out, "美金")
+
+	_, err = suite.execute("rates", "--lang", "ja")
+	suite.EqualError(err, "unsupported language ja")
+}
+
+func (suite *commandSuite) TestLocalizedErrors() {
+	_, err := suite.execute("rates", "XYZ")
+	suite.EqualError(err, `未知的幣別 "XYZ"`)
+	suite.ErrorIs(err, twfxr.ErrUnknownCurrency)
+
+	_, err = suite.execute("rates", "XYZ", "--lang", "en")
+	suite.EqualError(err, `unknown currency "XYZ"`)
+	suite.ErrorIs(err, twfxr.ErrUnknownCurrency)
+
+	_, err = suite.execute("convert", "100", "ZAR", "TWD", "--kind", "cash", "--lang", "zh-TW")
+	suite.EqualError(err, "南非幣 未掛牌本行買入現金匯率")
+
+	_, err = suite.execute("convert", "100", "ZAR", "TWD", "--kind", "cash", "--lang", "en")
+	suite.EqualError(err, "ZAR buying cash rate is not quoted")
+
+	_, err = parseRule("JPY selling < 0.25")
+	suite.EqualError(err, `invalid rule "JPY selling < 0.25": unknown field selling`)
+
+	language = twfxr.LanguageZhTW
+	_, err = parseRule("JPY selling < 0.25")
+	suite.EqualError(err, `無效的規則 "JPY selling < 0.25": 未知的欄位 selling`)
+}
+
+func (suite *commandSuite) TestRatesJSON() {
+	out, err := suite.execute("rates", "-o", "json", "ZAR")
+	suite.NoError(err)
+
+	var rows []map[string]interface{}
+	suite.NoError(json.Unmarshal([]byte(out), &rows))
+	suite.Len(rows, 1)
+	suite.Equal("2021-08-29T05:26:00+08:00", rows[0]["QuotedAt"])
+	suite.Equal("ZAR", rows[0]["Currency"])
+	suite.Nil(rows[0]["Buying-Cash"])
+	suite.Equal(1.872, rows[0]["Selling-Forward-180Days"])
+	suite.True(strings.Index(out, `"QuotedAt"`) < strings.Index(out, `"Currency"`))
+}
+
+func (suite *commandSuite) TestRatesCSV() {
+	out, err := suite.execute("rates", "-o", "csv", "ZAR", "JPY")
+	suite.NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	suite.Len(lines, 3)
+	suite.Equal("QuotedAt,Currency,Buying-Cash,Buying-Spot,Buying-Forward-10Days,Buying-Forward-30Days,"+
+		"Buying-Forward-60Days,Buying-Forward-90Days,Buying-Forward-120Days,Buying-Forward-150Days,"+
+		"Buying-Forward-180Days,Selling-Cash,Selling-Spot,Selling-Forward-10Days,Selling-Forward-30Days,"+
+		"Selling-Forward-60Days,Selling-Forward-90Days,Selling-Forward-120Days,Selling-Forward-150Days,"+
+		"Selling-Forward-180Days", lines[0])
+	suite.True(strings.HasPrefix(lines[1], "2021-08-29T05:26:00+08:00,ZAR,,1.851,1.832,"))
+	suite.True(strings.HasPrefix(lines[2], "2021-08-29T05:26:00+08:00,JPY,0.2449,0.2519,"))
+}
+
+func (suite *commandSuite) TestRatesTSV() {
+	out, err := suite.execute("rates", "-o", "tsv", "USD")
+	suite.NoError(err)
+	suite.Contains(out, "QuotedAt\tCurrency\tBuying-Cash\t")
+	suite.Contains(out, "2021-08-29T05:26:00+08:00\tUSD\t27.52\t27.845\t")
+}
+
+func (suite *commandSuite) TestRatesYAML() {
+	out, err := suite.execute("rates", "-o", "yaml", "ZAR")
+	suite.NoError(err)
+
+	var rows []map[string]interface{}
+	suite.NoError(yaml.Unmarshal([]byte(out), &rows))
+	suite.Len(rows, 1)
+	suite.Equal("ZAR", rows[0]["Currency"])
+	suite.Nil(rows[0]["Selling-Cash"])
+	suite.Equal(1.941, rows[0]["Selling-Spot"])
+}
+
+func (suite *commandSuite) TestRatesFromFile() {
+	httpmock.ZeroCallCounters()
+
+	out, err := suite.execute("rates", "--from-file", "../../../testdata/ExchangeRate@202108290526.csv", "-o", "csv", "USD")
+	suite.NoError(err)
+	suite.Contains(out, "2021-08-29T05:26:00+08:00,USD,27.52,27.845,")
+	suite.Zero(httpmock.GetTotalCallCount())
+
+	_, err = suite.execute("rates", "--from-file", "../../../testdata/missing.csv")
+	suite.Error(err)
+}
+
+func (suite *commandSuite) TestUnsupportedOutput() {
+	_, err := suite.execute("rates", "-o", "xml")
+	suite.Error(err)
+}
+
+func (suite *commandSuite) TestUnknownCurrency() {
+	_, err := suite.execute("rates", "XYZ")
+	suite.Error(err)
+}
+
+func (suite *commandSuite) TestConvert() {
+	out, err := suite.execute("convert", "1000", "usd", "TWD", "--side", "buy", "--kind", "spot", "--places", "0")
+	suite.NoError(err)
+	suite.Equal("1000 USD = 27845 TWD\n", out)
+}
+
+func (suite *commandSuite) TestGoldTable() {
+	out, err := suite.execute("gold")
+	suite.NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	suite.Len(lines, 4)
+	suite.Contains(lines[0], "本行買進(1公克)")
+	suite.Contains(lines[2], "新台幣 (TWD)")
+	suite.Contains(lines[2], "1605")
+	suite.Contains(lines[3], "美金 (USD)")
+	suite.Contains(lines[3], "58.12")
+}
+
+func (suite *commandSuite) TestGoldJSON() {
+	out, err := suite.execute("gold", "usd", "-o", "json")
+	suite.NoError(err)
+
+	var rows []map[string]interface{}
+	suite.NoError(json.Unmarshal([]byte(out), &rows))
+	suite.Equal([]map[string]interface{}{{
+		"QuotedAt": "2021-08-27T13:30:00+08:00",
+		"Currency": "USD",
+		"Buying":   57.4,
+		"Selling":  58.12,
+	}}, rows)
+}
+
+func (suite *commandSuite) TestGoldFromFile() {
+	httpmock.ZeroCallCounters()
+
+	out, err := suite.execute("gold", "TWD", "--from-file", "../../../testdata/en/GoldPassbook@202108271330.csv", "-o", "csv")
+	suite.NoError(err)
+	suite.Equal("QuotedAt,Currency,Buying,Selling\n2021-08-27T13:30:00+08:00,TWD,1605,1625\n", out)
+	suite.Zero(httpmock.GetTotalCallCount())
+
+	_, err = suite.execute("gold", "JPY", "--from-file", "../../../testdata/GoldPassbook@202108271330.csv", "--lang", "en")
+	suite.EqualError(err, "no gold quote in JPY")
+	suite.ErrorIs(err, twfxr.ErrNotFound)
+}
+
+func (suite *commandSuite) TestWatch() {
+	var payloads []map[string]interface{}
+	httpmock.RegisterResponder(http.MethodPost, "http://hooks.local/alerts",
+		func(req *http.Request) (*http.Response, error) {
+			suite.Equal("application/json", req.Header.Get("Content-Type"))
+
+			var payload map[string]interface{}
+			suite.NoError(json.NewDecoder(req.Body).Decode(&payload))
+			payloads = append(payloads, payload)
+
+			return newResponse(http.StatusNoContent, nil), nil
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	out, err := suite.executeContext(ctx, "watch", "JPY selling-cash < 0.26", "USD buying-spot > 30",
+		"--interval", "5ms", "--all-hours", "--webhook", "http://hooks.local/alerts", "--exec", "echo $TWFXR_CURRENCY $TWFXR_RATE")
+	suite.NoError(err)
+
+	// The threshold rule alerts only once although the rates are polled several times.
+	suite.Equal("2021-08-29T05:26:00+08:00 JPY selling-cash < 0.26: 0.2577\nJPY 0.2577\n", out)
+
+	if suite.Len(payloads, 1) {
+		suite.Equal("JPY selling-cash < 0.26", payloads[0]["Rule"])
+		suite.Equal("JPY", payloads[0]["Currency"])
+		suite.Equal(0.2577, payloads[0]["Rate"])
+		suite.Equal("2021-08-29T05:26:00+08:00", payloads[0]["QuotedAt"])
+	}
+}
+
+func (suite *commandSuite) TestWatchAlertTimeout() {
+	httpmock.RegisterResponder(http.MethodPost, "http://hooks.local/hanging",
+		func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// The shell is replaced by sleep, as the output of the command is captured until every process holding it exits.
+	out, err := suite.executeContext(ctx, "watch", "JPY selling-cash < 0.26", "--interval", "5ms", "--all-hours",
+		"--webhook", "http://hooks.local/hanging", "--exec", "exec sleep 5", "--alert-timeout", "20ms", "--lang", "en")
+	suite.NoError(err)
+	suite.Contains(out, `failed to run "exec sleep 5": signal: killed`)
+	suite.Contains(out, "failed to post webhook: Post \"http://hooks.local/hanging\": context deadline exceeded")
+	suite.Equal(1, strings.Count(out, "JPY selling-cash < 0.26: 0.2577"))
+
+	_, err = suite.execute("watch", "JPY selling-cash < 0.26", "--alert-timeout", "0s", "--lang", "en")
+	suite.EqualError(err, "invalid --alert-timeout 0s")
+}
+
+func (suite *commandSuite) TestWatchInvalidRule() {
+	_, err := suite.execute("watch", "JPY selling < 0.25")
+	suite.Error(err)
+}
+
+func TestParseRule(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		want  rule
+	}{
+		"threshold": {
+			input: "jpy selling-cash < 0.25",
+			want: rule{
+				text: "jpy selling-cash < 0.25", currency: twfxr.CurrencyJPY, side: twfxr.SideSelling,
+				kind: twfxr.RateKindCash, op: "<", value: 0.25,
+			},
+		},
+		"change in percentage": {
+			input: " USD  buying-spot change >= 0.5% ",
+			want: rule{
+				text: "USD buying-spot change >= 0.5%", currency: twfxr.CurrencyUSD, side: twfxr.SideBuying,
+				kind: twfxr.RateKindSpot, change: true, op: ">=", value: 0.5, percent: true,
+			},
+		},
+		"change in TWD": {
+			input: "EUR selling-spot change > 0.1",
+			want: rule{
+				text: "EUR selling-spot change > 0.1", currency: twfxr.CurrencyEUR, side: twfxr.SideSelling,
+				kind: twfxr.RateKindSpot, change: true, op: ">", value: 0.1,
+			},
+		},
+		"chinese name": {
+			input: "日圓 selling-cash < 0.25",
+			want: rule{
+				text: "日圓 selling-cash < 0.25", currency: twfxr.CurrencyJPY, side: twfxr.SideSelling,
+				kind: twfxr.RateKindCash, op: "<", value: 0.25,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r, err := parseRule(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, r)
+		})
+	}
+
+	for _, input := range []string{
+		"JPY selling-cash",
+		"XYZ selling-cash < 0.25",
+		"TWD selling-cash < 0.25",
+		"JPY selling-forward < 0.25",
+		"JPY selling-cash = 0.25",
+		"JPY selling-cash < 25%",
+		"JPY selling-cash rise > 0.25",
+	} {
+		_, err := parseRule(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestRuleEvaluate(t *testing.T) {
+	previous := map[twfxr.Currency]twfxr.CurrencyExchangeRate{
+		twfxr.CurrencyUSD: {Currency: "USD", BuyingSpot: 28, SellingCash: 28.5},
+	}
+	current := map[twfxr.Currency]twfxr.CurrencyExchangeRate{
+		twfxr.CurrencyUSD: {Currency: "USD", BuyingSpot: 27.86, SellingCash: 28.5},
+	}
+
+	testCases := map[string]struct {
+		rule     string
+		previous map[twfxr.Currency]twfxr.CurrencyExchangeRate
+		value    float64
+		ok       bool
+	}{
+		"When the rate is below the threshold, Then it matches": {
+			rule: "USD buying-spot < 27.9", value: 27.86, ok: true,
+		},
+		"When the rate is not offered, Then it does not match": {
+			rule: "USD buying-cash < 27.9",
+		},
+		"When the change in percentage exceeds the threshold, Then it matches": {
+			rule: "USD buying-spot change > 0.4%", previous: previous, value: 0.5, ok: true,
+		},
+		"When the change in TWD is below the threshold, Then it does not match": {
+			rule: "USD buying-spot change > 0.15", previous: previous, value: 0.14,
+		},
+		"When there is no previous revision, Then a change does not match": {
+			rule: "USD selling-cash change >= 0",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r, err := parseRule(tc.rule)
+			assert.NoError(t, err)
+
+			value, ok := r.evaluate(tc.previous, current)
+			assert.InDelta(t, tc.value, value, 1e-9)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestCommandSuite(t *testing.T) {
+	suite.Run(t, new(commandSuite))
+}
@@ -0,0 +1,105 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/spf13/cobra"
+)
+
+// Flags
+var (
+	convertSide   string
+	convertKind   string
+	convertPlaces int32
+)
+
+var (
+	convertCmd = &cobra.Command{
+		Use:   "convert AMOUNT FROM TO",
+		Short: "Convert an amount between currencies at the latest board rates",
+		Long: `Convert an amount between currencies at the latest board rates. Either currency
+can be TWD, converting between two foreign currencies goes through TWD.
+
+By default the bank buys the currency converted from and sells the currency
+converted to, use --side to use the same side for both currencies instead.`,
+		Example: `  twfxr convert 100 USD TWD
+  twfxr convert 100 USD TWD --side sell --kind cash
+  twfxr convert 10000 JPY USD --places 4`,
+		Args: cobra.ExactArgs(3),
+		RunE: runConvert,
+	}
+)
+
+func init() {
+	convertCmd.Flags().StringVar(&convertSide, "side", "auto", "side of the board rate: auto, buy or sell")
+	convertCmd.Flags().StringVar(&convertKind, "kind", "spot", "kind of the board rate: spot or cash")
+	convertCmd.Flags().Int32Var(&convertPlaces, "places", 2, "number of decimal places of the result")
+	rootCmd.AddCommand(convertCmd)
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	amount, err := twfxr.ParseDecimal(args[0])
+	if err != nil {
+		return errorf("invalid amount: %w", err)
+	}
+
+	from, err := parseCurrency(args[1], true)
+	if err != nil {
+		return err
+	}
+
+	to, err := parseCurrency(args[2], true)
+	if err != nil {
+		return err
+	}
+
+	side, err := parseSide(convertSide)
+	if err != nil {
+		return err
+	}
+
+	kind, err := parseRateKind(convertKind)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := getSnapshot(cmd)
+	if err != nil {
+		return err
+	}
+
+	converted, err := twfxr.ConvertDecimal(snapshot.Map(), amount, from, to, side, kind, convertPlaces, twfxr.RoundHalfUp)
+	if err != nil {
+		return localizeError(err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s = %s %s\n", amount, from, converted, to)
+
+	return nil
+}
+
+func parseSide(s string) (twfxr.Side, error) {
+	switch strings.ToLower(s) {
+	case "auto":
+		return twfxr.SideAuto, nil
+	case "buy", "buying":
+		return twfxr.SideBuying, nil
+	case "sell", "selling":
+		return twfxr.SideSelling, nil
+	default:
+		return 0, errorf("unknown side %q", s)
+	}
+}
+
+func parseRateKind(s string) (twfxr.RateKind, error) {
+	switch strings.ToLower(s) {
+	case "spot":
+		return twfxr.RateKindSpot, nil
+	case "cash":
+		return twfxr.RateKindCash, nil
+	default:
+		return 0, errorf("unknown rate kind %q", s)
+	}
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"time"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/spf13/cobra"
+)
+
+var (
+	goldCmd = &cobra.Command{
+		Use:   "gold [CURRENCY...]",
+		Short: "List the latest gold passbook prices",
+		Long: `List the latest gold passbook (黃金存摺) prices per gram of the given currencies,
+or of every currency quoted by the bank in the order published when no currency
+is given.
+
+--from-file reads a previously downloaded price file, e.g.
+GoldPassbook@202108271330.csv, instead of the bank.`,
+		Example: `  twfxr gold
+  twfxr gold USD -o json`,
+		RunE: runGold,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(goldCmd)
+}
+
+func runGold(cmd *cobra.Command, args []string) error {
+	selected := make([]twfxr.Currency, 0, len(args))
+	for _, arg := range args {
+		currency, err := parseCurrency(arg, true)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, currency)
+	}
+
+	format, err := parseOutput(output)
+	if err != nil {
+		return err
+	}
+
+	var quotes twfxr.GoldQuotes
+
+	if fromFile != "" {
+		quotes, err = twfxr.ParseGoldFile(fromFile)
+	} else {
+		quotes, err = twfxr.GetGoldQuotes(cmd.Context())
+	}
+	if err != nil {
+		return localizeError(err)
+	}
+
+	list := quotes.Quotes
+	if len(selected) > 0 {
+		list = make([]twfxr.GoldQuote, 0, len(selected))
+		for _, currency := range selected {
+			quote, ok := quotes.Get(currency)
+			if !ok {
+				return localize(twfxr.ErrNotFound, "no gold quote in %s", currency)
+			}
+			list = append(list, quote)
+		}
+	}
+
+	if format != outputTable {
+		quotedAt := quotes.Metadata.QuotedAt.Format(time.RFC3339)
+
+		rows := make([][]interface{}, 0, len(list))
+		for _, quote := range list {
+			rows = append(rows, []interface{}{quotedAt, string(quote.Currency), quote.Buying, quote.Selling})
+		}
+
+		return writeRows(cmd.OutOrStdout(), format, []string{"QuotedAt", "Currency", "Buying", "Selling"}, rows)
+	}
+
+	data := make([][]string, 0, len(list))
+	for _, quote := range list {
+		data = append(data, []string{currencyName(quote.Currency), quote.Buying.String(), quote.Selling.String()})
+	}
+
+	renderTable(cmd.OutOrStdout(), []string{tr("Pricing Currency"), tr("Buying per Gram"), tr("Selling per Gram")}, data)
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"time"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/spf13/cobra"
+)
+
+// Flags
+var (
+	historyFrom string
+	historyTo   string
+)
+
+var (
+	historyCmd = &cobra.Command{
+		Use:   "history CURRENCY",
+		Short: "List the daily board rates of a currency over a date range",
+		Long: `List the daily board rates of a currency between --from and --to, both
+inclusive and in the format of 2006-01-02. --to defaults to today and --from
+defaults to 3 months before --to.`,
+		Example: `  twfxr history USD
+  twfxr history JPY --from 2021-06-01 --to 2021-08-31`,
+		Args: cobra.ExactArgs(1),
+		RunE: runHistory,
+	}
+)
+
+func init() {
+	historyCmd.Flags().StringVar(&historyFrom, "from", "", "first date, e.g. 2021-06-01")
+	historyCmd.Flags().StringVar(&historyTo, "to", "", "last date, e.g. 2021-08-31")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if fromFile != "" {
+		return errorf("--from-file is not supported by %s", cmd.Name())
+	}
+
+	currency, err := parseCurrency(args[0], false)
+	if err != nil {
+		return err
+	}
+
+	to := time.Now()
+	if historyTo != "" {
+		if to, err = time.Parse("2006-01-02", historyTo); err != nil {
+			return errorf("invalid --to: %w", err)
+		}
+	}
+
+	from := to.AddDate(0, -3, 0)
+	if historyFrom != "" {
+		if from, err = time.Parse("2006-01-02", historyFrom); err != nil {
+			return errorf("invalid --from: %w", err)
+		}
+	}
+
+	format, err := parseOutput(output)
+	if err != nil {
+		return err
+	}
+
+	history, err := twfxr.GetCurrencyHistory(cmd.Context(), currency, from, to)
+	if err != nil {
+		return localizeError(err)
+	}
+
+	if format != outputTable {
+		rows := make([][]interface{}, 0, len(history))
+		for _, exchangeRate := range history {
+			date := exchangeRate.Date.Format("2006-01-02")
+			rows = append(rows, append([]interface{}{date}, rateValues(exchangeRate.CurrencyExchangeRate)...))
+		}
+
+		return writeRows(cmd.OutOrStdout(), format, append([]string{"Date"}, rateColumns...), rows)
+	}
+
+	data := make([][]string, 0, len(history))
+	for _, exchangeRate := range history {
+		data = append(data, []string{
+			exchangeRate.Date.Format("2006-01-02"),
+			formatRate(exchangeRate.BuyingCash),
+			formatRate(exchangeRate.BuyingSpot),
+			formatRate(exchangeRate.SellingCash),
+			formatRate(exchangeRate.SellingSpot),
+		})
+	}
+
+	renderTable(cmd.OutOrStdout(), []string{tr("Date"), tr("Buying: Cash"), tr("Buying: Spot"), tr("Selling: Cash"), tr("Selling: Spot")}, data)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mkfsn/twfxr"
+)
+
+// translations are the texts of the CLI in Traditional Chinese keyed by the ones in English, which are used as they
+// are with --lang en.
+var translations = map[string]string{
+	// Table headers
+	"Currency":      "外幣",
+	"Date":          "日期",
+	"Buying: Cash":  "本行買入:現金",
+	"Buying: Spot":  "本行買入:即期",
+	"Selling: Cash": "本行賣出:現金",
+	"Selling: Spot": "本行賣出:即期",
+
+	"Pricing Currency": "計價幣別",
+	"Buying per Gram":  "本行買進(1公克)",
+	"Selling per Gram": "本行賣出(1公克)",
+
+	// Sides and kinds of the board rates
+	"buying":  "本行買入",
+	"selling": "本行賣出",
+	"cash":    "現金",
+	"spot":    "即期",
+
+	// Errors
+	"unsupported output %s":                 "不支援的輸出格式 %s",
+	"unknown currency %q":                   "未知的幣別 %q",
+	"no such currency %s":                   "查無幣別 %s",
+	"no gold quote in %s":                   "查無%s黃金存摺牌價",
+	"invalid amount: %w":                    "無效的金額: %w",
+	"unknown side %q":                       "未知的買賣別 %q",
+	"unknown rate kind %q":                  "未知的匯率種類 %q",
+	"%s %s %s rate is not quoted":           "%s 未掛牌%s%s匯率",
+	"invalid --from: %w":                    "無效的 --from: %w",
+	"invalid --to: %w":                      "無效的 --to: %w",
+	"invalid --interval %s":                 "無效的 --interval %s",
+	"invalid --alert-timeout %s":            "無效的 --alert-timeout %s",
+	"--from-file is not supported by %s":    "%s 不支援 --from-file",
+	"the bank is unavailable: %v":           "臺灣銀行暫時無法提供服務: %v",
+	"no rates found: %v":                    "查無匯率: %v",
+	"unexpected response from the bank: %v": "臺灣銀行的回應無法解析: %v",
+	"failed to run %q: %v\n":                "無法執行 %q: %v\n",
+	"failed to post webhook: %v\n":          "無法送出 webhook: %v\n",
+
+	"invalid rule %q: expected CURRENCY FIELD [change] OP VALUE": "無效的規則 %q: 格式應為 CURRENCY FIELD [change] OP VALUE",
+	"invalid rule %q: %w":                  "無效的規則 %q: %w",
+	"invalid rule %q: unknown field %s":    "無效的規則 %q: 未知的欄位 %s",
+	"invalid rule %q: unknown operator %s": "無效的規則 %q: 未知的運算子 %s",
+	"invalid rule %q: invalid value %s":    "無效的規則 %q: 無效的數值 %s",
+}
+
+// parseLanguage validates the --lang flag, which is either zh-TW or en.
+func parseLanguage(s string) (string, error) {
+	return twfxr.ParseLanguage(s)
+}
+
+// isEnglish reports whether --lang is en.
+func isEnglish() bool {
+	return strings.EqualFold(language, twfxr.LanguageEn)
+}
+
+// tr translates a text in English into the language of --lang.
+func tr(s string) string {
+	if isEnglish() {
+		return s
+	}
+
+	if t, ok := translations[s]; ok {
+		return t
+	}
+
+	return s
+}
+
+// errorf is fmt.Errorf with the format translated into the language of --lang.
+func errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(tr(format), args...)
+}
+
+// localizedError is an error with the message in the language of --lang, which still wraps the original error.
+type localizedError struct {
+	msg string
+	err error
+}
+
+func (e *localizedError) Error() string {
+	return e.msg
+}
+
+func (e *localizedError) Unwrap() error {
+	return e.err
+}
+
+// localize replaces the message of err with the format translated into the language of --lang, keeping err in the
+// chain for errors.Is and errors.As.
+func localize(err error, format string, args ...interface{}) error {
+	return &localizedError{msg: fmt.Sprintf(tr(format), args...), err: err}
+}
+
+// localizeError translates the errors returned by the library which are commonly shown to the user. The other errors
+// are returned as they are.
+func localizeError(err error) error {
+	if err == nil || isEnglish() {
+		return err
+	}
+
+	var unavailable *twfxr.RateUnavailableError
+
+	switch {
+	case errors.As(err, &unavailable):
+		return localize(err, "%s %s %s rate is not quoted", unavailable.Currency.Name(twfxr.LanguageZhTW),
+			tr(unavailable.Side.String()), tr(unavailable.Kind.String()))
+	case errors.Is(err, twfxr.ErrUpstreamUnavailable):
+		return localize(err, "the bank is unavailable: %v", err)
+	case errors.Is(err, twfxr.ErrNotFound):
+		return localize(err, "no rates found: %v", err)
+	case errors.Is(err, twfxr.ErrUnexpectedContentType):
+		return localize(err, "unexpected response from the bank: %v", err)
+	default:
+		return err
+	}
+}
+
+// currencyName returns the currency code along with its name in the language of --lang, e.g. 美金 (USD).
+func currencyName(currency twfxr.Currency) string {
+	lang := twfxr.LanguageZhTW
+	if isEnglish() {
+		lang = twfxr.LanguageEn
+	}
+
+	name := currency.Name(lang)
+	if name == "" {
+		return string(currency)
+	}
+
+	return fmt.Sprintf("%s (%s)", name, currency)
+}
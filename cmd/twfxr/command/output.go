@@ -0,0 +1,209 @@
+package command
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mkfsn/twfxr"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+	outputTSV   = "tsv"
+	outputYAML  = "yaml"
+)
+
+// parseOutput validates the --output flag, which defaults to table.
+func parseOutput(s string) (string, error) {
+	switch format := strings.ToLower(s); format {
+	case "":
+		return outputTable, nil
+	case outputTable, outputJSON, outputCSV, outputTSV, outputYAML:
+		return format, nil
+	default:
+		return "", errorf("unsupported output %s", s)
+	}
+}
+
+// rateColumns are the names of the columns of an exchange rate, which are the same as its JSON keys.
+var rateColumns = []string{
+	"Currency",
+	"Buying-Cash",
+	"Buying-Spot",
+	"Buying-Forward-10Days",
+	"Buying-Forward-30Days",
+	"Buying-Forward-60Days",
+	"Buying-Forward-90Days",
+	"Buying-Forward-120Days",
+	"Buying-Forward-150Days",
+	"Buying-Forward-180Days",
+	"Selling-Cash",
+	"Selling-Spot",
+	"Selling-Forward-10Days",
+	"Selling-Forward-30Days",
+	"Selling-Forward-60Days",
+	"Selling-Forward-90Days",
+	"Selling-Forward-120Days",
+	"Selling-Forward-150Days",
+	"Selling-Forward-180Days",
+}
+
+// rateValues returns the values of an exchange rate in the order of rateColumns. Each value is either a string or a
+// twfxr.Rate.
+func rateValues(r twfxr.CurrencyExchangeRate) []interface{} {
+	return []interface{}{
+		string(r.Currency),
+		r.BuyingCash,
+		r.BuyingSpot,
+		r.BuyingForward10Days,
+		r.BuyingForward30Days,
+		r.BuyingForward60Days,
+		r.BuyingForward90Days,
+		r.BuyingForward120Days,
+		r.BuyingForward150Days,
+		r.BuyingForward180Days,
+		r.SellingCash,
+		r.SellingSpot,
+		r.SellingForward10Days,
+		r.SellingForward30Days,
+		r.SellingForward60Days,
+		r.SellingForward90Days,
+		r.SellingForward120Days,
+		r.SellingForward150Days,
+		r.SellingForward180Days,
+	}
+}
+
+// writeRows writes the rows in a machine-readable format, i.e. every format but table.
+func writeRows(w io.Writer, format string, columns []string, rows [][]interface{}) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, columns, rows)
+	case outputCSV:
+		return writeCSV(w, ',', columns, rows)
+	case outputTSV:
+		return writeCSV(w, '\t', columns, rows)
+	case outputYAML:
+		return writeYAML(w, columns, rows)
+	default:
+		return fmt.Errorf("unsupported output %s", format)
+	}
+}
+
+// writeJSON writes the rows as an array of objects, keeping the order of the columns.
+func writeJSON(w io.Writer, columns []string, rows [][]interface{}) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("[")
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		buf.WriteString("{")
+		for j, value := range row {
+			if j > 0 {
+				buf.WriteString(",")
+			}
+
+			key, _ := json.Marshal(columns[j])
+			b, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+
+			buf.Write(key)
+			buf.WriteString(":")
+			buf.Write(b)
+		}
+		buf.WriteString("}")
+	}
+	buf.WriteString("]")
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteString("\n")
+
+	_, err := out.WriteTo(w)
+
+	return err
+}
+
+// writeCSV writes the rows with a header, leaving the rates not offered by the bank empty.
+func writeCSV(w io.Writer, comma rune, columns []string, rows [][]interface{}) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			switch v := value.(type) {
+			case twfxr.Rate:
+				if f, ok := v.Float64(); ok {
+					record[i] = strconv.FormatFloat(f, 'f', -1, 64)
+				}
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// writeYAML writes the rows as a sequence of mappings, keeping the order of the columns.
+func writeYAML(w io.Writer, columns []string, rows [][]interface{}) error {
+	doc := &yaml.Node{Kind: yaml.SequenceNode}
+
+	for _, row := range rows {
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		for i, value := range row {
+			node := &yaml.Node{Kind: yaml.ScalarNode}
+
+			switch v := value.(type) {
+			case twfxr.Rate:
+				if f, ok := v.Float64(); ok {
+					node.Tag, node.Value = "!!float", strconv.FormatFloat(f, 'f', -1, 64)
+				} else {
+					node.Tag, node.Value = "!!null", "null"
+				}
+			case twfxr.Decimal:
+				node.Tag, node.Value = "!!float", v.String()
+			default:
+				node.Tag, node.Value = "!!str", fmt.Sprint(v)
+			}
+
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: columns[i]}, node)
+		}
+		doc.Content = append(doc.Content, mapping)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"time"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ratesCmd = &cobra.Command{
+		Use:   "rates [CURRENCY...]",
+		Short: "List the latest board rates",
+		Long: `List the latest cash and spot board rates of the given currencies, or of every
+currency quoted by the bank when no currency is given.`,
+		Example: `  twfxr rates
+  twfxr rates USD JPY`,
+		Args: currencyArgs,
+		RunE: runRates,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(ratesCmd)
+}
+
+func runRates(cmd *cobra.Command, args []string) error {
+	selected := make([]twfxr.Currency, 0, len(args))
+	for _, arg := range args {
+		currency, err := parseCurrency(arg, false)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, currency)
+	}
+
+	format, err := parseOutput(output)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := getSnapshot(cmd)
+	if err != nil {
+		return err
+	}
+
+	rates := snapshot.Rates()
+	if len(selected) > 0 {
+		rates = make([]twfxr.CurrencyExchangeRate, 0, len(selected))
+		for _, currency := range selected {
+			exchangeRate, ok := snapshot.Get(currency)
+			if !ok {
+				return localize(twfxr.ErrNotFound, "no such currency %s", currency)
+			}
+			rates = append(rates, exchangeRate)
+		}
+	}
+
+	if format != outputTable {
+		quotedAt := snapshot.Metadata.QuotedAt.Format(time.RFC3339)
+
+		rows := make([][]interface{}, 0, len(rates))
+		for _, exchangeRate := range rates {
+			rows = append(rows, append([]interface{}{quotedAt}, rateValues(exchangeRate)...))
+		}
+
+		return writeRows(cmd.OutOrStdout(), format, append([]string{"QuotedAt"}, rateColumns...), rows)
+	}
+
+	data := make([][]string, 0, len(rates))
+	for _, exchangeRate := range rates {
+		data = append(data, []string{
+			currencyName(exchangeRate.Currency),
+			formatRate(exchangeRate.BuyingCash),
+			formatRate(exchangeRate.BuyingSpot),
+			formatRate(exchangeRate.SellingCash),
+			formatRate(exchangeRate.SellingSpot),
+		})
+	}
+
+	renderTable(cmd.OutOrStdout(), []string{tr("Currency"), tr("Buying: Cash"), tr("Buying: Spot"), tr("Selling: Cash"), tr("Selling: Spot")}, data)
+
+	return nil
+}
@@ -3,9 +3,9 @@ package command
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
 	"os"
-	"strings"
+	"os/signal"
 
 	"github.com/mkfsn/twfxr"
 	"github.com/olekukonko/tablewriter"
@@ -14,83 +14,105 @@ import (
 
 // Persistent Flags
 var (
-	output string
+	output   string
+	fromFile string
+	language string
 )
 
 var (
 	rootCmd = &cobra.Command{
 		Use:   "twfxr",
-		Short: "A generator for Cobra based Applications",
-		Long: `Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-		Run: func(cmd *cobra.Command, args []string) {
-			results, _, err := twfxr.GetCurrencyExchangeRates(context.Background())
-			if err != nil {
-				log.Printf("error: %s\n", err)
-				return
-			}
-
-			switch strings.ToLower(output) {
-			case "":
-				toTableRow := func(exchangeRate twfxr.CurrencyExchangeRate) []string {
-					toValue := func(f float64) string {
-						if f == 0 {
-							return "-"
-						}
-						return fmt.Sprintf("%f", f)
-					}
-
-					return []string{
-						exchangeRate.Currency,
-						toValue(exchangeRate.BuyingCash),
-						toValue(exchangeRate.BuyingSpot),
-						toValue(exchangeRate.SellingCash),
-						toValue(exchangeRate.SellingSpot),
-					}
-				}
-
-				data := [][]string{
-					toTableRow(results[twfxr.CurrencyUSD]),
-					toTableRow(results[twfxr.CurrencyHKD]),
-					toTableRow(results[twfxr.CurrencyGBP]),
-					toTableRow(results[twfxr.CurrencyAUD]),
-					toTableRow(results[twfxr.CurrencyCAD]),
-					toTableRow(results[twfxr.CurrencySGD]),
-					toTableRow(results[twfxr.CurrencyCHF]),
-					toTableRow(results[twfxr.CurrencyJPY]),
-					toTableRow(results[twfxr.CurrencyZAR]),
-					toTableRow(results[twfxr.CurrencySEK]),
-					toTableRow(results[twfxr.CurrencyNZD]),
-					toTableRow(results[twfxr.CurrencyTHB]),
-					toTableRow(results[twfxr.CurrencyPHP]),
-					toTableRow(results[twfxr.CurrencyIDR]),
-					toTableRow(results[twfxr.CurrencyEUR]),
-					toTableRow(results[twfxr.CurrencyKRW]),
-					toTableRow(results[twfxr.CurrencyVND]),
-					toTableRow(results[twfxr.CurrencyMYR]),
-					toTableRow(results[twfxr.CurrencyCNY]),
-				}
-
-				table := tablewriter.NewWriter(os.Stdout)
-				table.SetHeader([]string{"外幣", "本行買入:現金", "本行買入:即期", "本行賣出:現金", "本行賣出:即期"})
-				table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-				table.SetCenterSeparator("|")
-				table.SetAlignment(tablewriter.ALIGN_RIGHT)
-				table.AppendBulk(data)
-				table.Render()
-
-			default:
-				_, _ = fmt.Fprintf(cmd.OutOrStderr(), "unsupported output %s\n", output)
-			}
-		},
+		Short: "Query the foreign exchange board rates of Bank of Taiwan",
+		Long: `twfxr queries the foreign exchange board rates published by Bank of Taiwan
+(https://rate.bot.com.tw/xrt?Lang=zh-TW).
+
+Running twfxr without a subcommand is the same as "twfxr rates", which lists
+the latest board rates of every currency.`,
+		Args:              cobra.NoArgs,
+		SilenceUsage:      true,
+		PersistentPreRunE: validateLanguage,
+		RunE:              runRates,
 	}
 )
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "output")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "output format: table, json, csv, tsv or yaml")
+	rootCmd.PersistentFlags().StringVar(&fromFile, "from-file", "",
+		"read the rates from a previously downloaded file, e.g. ExchangeRate@202108290526.csv, instead of the bank")
+	rootCmd.PersistentFlags().StringVar(&language, "lang", twfxr.LanguageZhTW,
+		"language of the table headers, the currency names and the error messages: zh-TW or en")
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// validateLanguage validates the --lang flag before running any command.
+func validateLanguage(cmd *cobra.Command, args []string) error {
+	lang, err := parseLanguage(language)
+	if err != nil {
+		return err
+	}
+
+	language = lang
+
+	return nil
+}
+
+// getSnapshot returns the latest board rates, or the rates in the file given by --from-file.
+func getSnapshot(cmd *cobra.Command) (twfxr.Snapshot, error) {
+	if fromFile != "" {
+		snapshot, err := twfxr.ParseSnapshotFile(fromFile)
+		return snapshot, localizeError(err)
+	}
+
+	snapshot, err := twfxr.GetSnapshot(cmd.Context())
+
+	return snapshot, localizeError(err)
+}
+
+// parseCurrency parses a currency code case-insensitively, or a name of the currency, e.g. 美金. TWD is only accepted
+// when allowTWD is true.
+func parseCurrency(s string, allowTWD bool) (twfxr.Currency, error) {
+	currency, err := twfxr.ParseCurrency(s)
+	if err != nil {
+		return "", localize(err, "unknown currency %q", s)
+	}
+
+	if currency == twfxr.CurrencyTWD && !allowTWD {
+		return "", localize(twfxr.ErrUnknownCurrency, "unknown currency %q", s)
+	}
+
+	return currency, nil
+}
+
+// currencyArgs validates that every argument is a currency quoted by the bank.
+func currencyArgs(cmd *cobra.Command, args []string) error {
+	for _, arg := range args {
+		if _, err := parseCurrency(arg, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatRate(rate twfxr.Rate) string {
+	f, ok := rate.Float64()
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%f", f)
+}
+
+func renderTable(w io.Writer, header []string, data [][]string) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.AppendBulk(data)
+	table.Render()
 }
@@ -0,0 +1,124 @@
+package command
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mkfsn/twfxr"
+)
+
+// rule is an alert rule of the watch command, which is either a threshold of a rate, e.g. "JPY selling-cash < 0.25",
+// or a threshold of the change of a rate between two revisions, e.g. "USD buying-spot change > 0.5%".
+type rule struct {
+	text     string
+	currency twfxr.Currency
+	side     twfxr.Side
+	kind     twfxr.RateKind
+	change   bool
+	op       string
+	value    float64
+	percent  bool
+}
+
+// parseRule parses a rule in the form of "CURRENCY FIELD [change] OP VALUE[%]", where FIELD is one of buying-cash,
+// buying-spot, selling-cash and selling-spot, and OP is one of <, <=, > and >=. A percentage is only allowed for a
+// change.
+func parseRule(s string) (rule, error) {
+	fields := strings.Fields(s)
+
+	r := rule{text: strings.Join(fields, " ")}
+
+	if len(fields) == 5 && fields[2] == "change" {
+		r.change = true
+		fields = append(fields[:2], fields[3:]...)
+	}
+
+	if len(fields) != 4 {
+		return rule{}, errorf("invalid rule %q: expected CURRENCY FIELD [change] OP VALUE", s)
+	}
+
+	currency, err := parseCurrency(fields[0], false)
+	if err != nil {
+		return rule{}, errorf("invalid rule %q: %w", s, err)
+	}
+	r.currency = currency
+
+	switch strings.ToLower(fields[1]) {
+	case "buying-cash":
+		r.side, r.kind = twfxr.SideBuying, twfxr.RateKindCash
+	case "buying-spot":
+		r.side, r.kind = twfxr.SideBuying, twfxr.RateKindSpot
+	case "selling-cash":
+		r.side, r.kind = twfxr.SideSelling, twfxr.RateKindCash
+	case "selling-spot":
+		r.side, r.kind = twfxr.SideSelling, twfxr.RateKindSpot
+	default:
+		return rule{}, errorf("invalid rule %q: unknown field %s", s, fields[1])
+	}
+
+	switch fields[2] {
+	case "<", "<=", ">", ">=":
+		r.op = fields[2]
+	default:
+		return rule{}, errorf("invalid rule %q: unknown operator %s", s, fields[2])
+	}
+
+	value := fields[3]
+	if r.change && strings.HasSuffix(value, "%") {
+		r.percent = true
+		value = strings.TrimSuffix(value, "%")
+	}
+
+	if r.value, err = strconv.ParseFloat(value, 64); err != nil {
+		return rule{}, errorf("invalid rule %q: invalid value %s", s, fields[3])
+	}
+
+	return r, nil
+}
+
+// rate returns the rate of the rule in the given exchange rate and whether it is offered by the bank.
+func (r rule) rate(exchangeRate twfxr.CurrencyExchangeRate) (float64, bool) {
+	if r.kind == twfxr.RateKindCash {
+		return exchangeRate.Cash(r.side)
+	}
+	return exchangeRate.Spot(r.side)
+}
+
+// evaluate returns the value compared by the rule, i.e. the rate or the magnitude of its change since the previous
+// revision, and whether the rule matches. A change rule never matches without a previous revision.
+func (r rule) evaluate(previous, current map[twfxr.Currency]twfxr.CurrencyExchangeRate) (float64, bool) {
+	rate, ok := r.rate(current[r.currency])
+	if !ok {
+		return 0, false
+	}
+
+	value := rate
+
+	if r.change {
+		if previous == nil {
+			return 0, false
+		}
+
+		old, ok := r.rate(previous[r.currency])
+		if !ok {
+			return 0, false
+		}
+
+		value = math.Abs(rate - old)
+		if r.percent {
+			value = value / old * 100
+		}
+	}
+
+	switch r.op {
+	case "<":
+		return value, value < r.value
+	case "<=":
+		return value, value <= r.value
+	case ">":
+		return value, value > r.value
+	default:
+		return value, value >= r.value
+	}
+}
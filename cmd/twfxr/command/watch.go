@@ -0,0 +1,213 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/spf13/cobra"
+)
+
+// Flags
+var (
+	watchInterval time.Duration
+	watchExec     string
+	watchWebhook  string
+	watchAllHours bool
+	watchTimeout  time.Duration
+)
+
+var (
+	watchCmd = &cobra.Command{
+		Use:   "watch RULE...",
+		Short: "Alert when the board rates match the given rules",
+		Long: `Poll the latest board rates and alert whenever a rule matches. A rule is either
+a threshold of a rate, e.g. "JPY selling-cash < 0.25", or a threshold of the
+change of a rate between two revisions, e.g. "USD buying-spot change > 0.5%".
+
+The fields are buying-cash, buying-spot, selling-cash and selling-spot, and the
+operators are <, <=, > and >=. A change is the magnitude of the difference from
+the previous revision, either in TWD or in percentage of the previous rate.
+
+A threshold rule alerts when it starts to match, while a change rule alerts at
+every revision it matches. Every alert is printed to stdout, and optionally sent
+to a shell command by --exec or to a webhook by --webhook as a JSON payload,
+each of which is given up after --alert-timeout.
+The shell command also gets the alert in the environment variables TWFXR_RULE,
+TWFXR_CURRENCY, TWFXR_RATE, TWFXR_VALUE and TWFXR_QUOTED_AT.
+
+Only Taiwan business hours are polled unless --all-hours is given.`,
+		Example: `  twfxr watch "JPY selling-cash < 0.25"
+  twfxr watch "USD buying-spot change > 0.5%" --interval 5m --webhook https://example.com/alerts
+  twfxr watch "EUR selling-spot <= 33" --exec 'notify-send "$TWFXR_RULE"'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runWatch,
+	}
+)
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Minute, "interval between polls")
+	watchCmd.Flags().StringVar(&watchExec, "exec", "", "shell command to run for every alert")
+	watchCmd.Flags().StringVar(&watchWebhook, "webhook", "", "URL to POST every alert to as JSON")
+	watchCmd.Flags().BoolVar(&watchAllHours, "all-hours", false, "poll outside of Taiwan business hours as well")
+	watchCmd.Flags().DurationVar(&watchTimeout, "alert-timeout", 10*time.Second, "timeout of --exec and --webhook for every alert")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// alert is a match of a rule, which is also the JSON payload of the webhook.
+type alert struct {
+	Rule     string
+	Currency twfxr.Currency
+	// Rate is the current rate of the field of the rule.
+	Rate float64
+	// Value is the value compared by the rule, i.e. the rate or its change.
+	Value    float64
+	QuotedAt time.Time
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if fromFile != "" {
+		return errorf("--from-file is not supported by %s", cmd.Name())
+	}
+
+	if watchInterval <= 0 {
+		return errorf("invalid --interval %s", watchInterval)
+	}
+
+	if watchTimeout <= 0 {
+		return errorf("invalid --alert-timeout %s", watchTimeout)
+	}
+
+	rules := make([]rule, 0, len(args))
+	for _, arg := range args {
+		r, err := parseRule(arg)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, r)
+	}
+
+	var opts []twfxr.WatchOption
+	if watchAllHours {
+		opts = append(opts, twfxr.WatchOffHours())
+	}
+
+	matched := make([]bool, len(rules))
+
+	var previous map[twfxr.Currency]twfxr.CurrencyExchangeRate
+
+	for update := range twfxr.Watch(cmd.Context(), watchInterval, opts...) {
+		if update.Err != nil {
+			fmt.Fprintln(cmd.ErrOrStderr(), localizeError(update.Err))
+			continue
+		}
+
+		for i, r := range rules {
+			value, ok := r.evaluate(previous, update.Rates)
+
+			// A threshold rule only alerts when it starts to match.
+			if ok && (r.change || !matched[i]) {
+				rate, _ := r.rate(update.Rates[r.currency])
+				fire(cmd, alert{
+					Rule:     r.text,
+					Currency: r.currency,
+					Rate:     rate,
+					Value:    value,
+					QuotedAt: update.Metadata.QuotedAt,
+				})
+			}
+
+			matched[i] = ok
+		}
+
+		previous = update.Rates
+	}
+
+	return nil
+}
+
+// fire sends the alert to stdout and the configured actions, each of which is given up after --alert-timeout so that a
+// hanging action does not stop polling. The failures of the actions are reported to stderr without stopping watching.
+func fire(cmd *cobra.Command, a alert) {
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %s: %s\n", a.QuotedAt.Format(time.RFC3339), a.Rule, formatFloat(a.Value))
+
+	if watchExec != "" {
+		ctx, cancel := context.WithTimeout(cmd.Context(), watchTimeout)
+		err := runAlertCommand(ctx, cmd, watchExec, a)
+		cancel()
+
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), tr("failed to run %q: %v\n"), watchExec, err)
+		}
+	}
+
+	if watchWebhook != "" {
+		ctx, cancel := context.WithTimeout(cmd.Context(), watchTimeout)
+		err := postWebhook(ctx, watchWebhook, a)
+		cancel()
+
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), tr("failed to post webhook: %v\n"), err)
+		}
+	}
+}
+
+// runAlertCommand runs the shell command with the alert in the environment variables and as JSON in stdin. The shell is
+// killed when ctx is done.
+func runAlertCommand(ctx context.Context, cmd *cobra.Command, command string, a alert) error {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	c := exec.CommandContext(ctx, "sh", "-c", command)
+	c.Env = append(os.Environ(),
+		"TWFXR_RULE="+a.Rule,
+		"TWFXR_CURRENCY="+string(a.Currency),
+		"TWFXR_RATE="+formatFloat(a.Rate),
+		"TWFXR_VALUE="+formatFloat(a.Value),
+		"TWFXR_QUOTED_AT="+a.QuotedAt.Format(time.RFC3339),
+	)
+	c.Stdin = bytes.NewReader(payload)
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+
+	return c.Run()
+}
+
+// postWebhook posts the alert to the URL as JSON.
+func postWebhook(ctx context.Context, url string, a alert) error {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: unexpected status %s", url, resp.Status)
+	}
+
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
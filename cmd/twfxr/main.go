@@ -1,9 +1,13 @@
 package main
 
 import (
+	"os"
+
 	"github.com/mkfsn/twfxr/cmd/twfxr/command"
 )
 
 func main() {
-	_ = command.Execute()
+	if err := command.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
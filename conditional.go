@@ -0,0 +1,81 @@
+package twfxr
+
+import (
+	"fmt"
+)
+
+// parsedSnapshot is the last parsed rates of a rate file, which are reused when the file is not modified.
+type parsedSnapshot struct {
+	etag         string
+	lastModified string
+	snapshot     Snapshot
+}
+
+// lastFile returns the last download of the given URL from the cache, or from the client itself without a cache.
+func (c *Client) lastFile(url string) (CachedFile, bool, error) {
+	if c.cache == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		file, ok := c.lastFiles[url]
+
+		return file, ok, nil
+	}
+
+	file, ok, err := c.cache.Get(url)
+	if err != nil {
+		return CachedFile{}, false, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	return file, ok, nil
+}
+
+// storeFile stores the download of the given URL in the cache. Without a cache, the client keeps the files which have
+// validators and may change only to revalidate them, so they are stale immediately.
+func (c *Client) storeFile(url string, file CachedFile, historical bool) error {
+	if c.cache == nil {
+		if historical || (file.ETag == "" && file.LastModified == "") {
+			return nil
+		}
+
+		file.ExpiresAt = c.now()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.lastFiles[url] = file
+
+		return nil
+	}
+
+	if err := c.cache.Set(url, file); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	return nil
+}
+
+// lastSnapshot returns the last parsed rates of the given URL if they are parsed from the same version of the file.
+func (c *Client) lastSnapshot(url string, file CachedFile) (Snapshot, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last, ok := c.lastParse[url]
+	if !ok || last.etag != file.ETag || last.lastModified != file.LastModified {
+		return Snapshot{}, false
+	}
+
+	return last.snapshot, true
+}
+
+// rememberSnapshot keeps the parsed rates of the given URL to reuse them when the file is not modified.
+func (c *Client) rememberSnapshot(url string, file CachedFile, snapshot Snapshot) {
+	if file.ETag == "" && file.LastModified == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastParse[url] = parsedSnapshot{etag: file.ETag, lastModified: file.LastModified, snapshot: snapshot}
+}
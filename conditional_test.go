@@ -0,0 +1,134 @@
+package twfxr_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testETag         = `"5f2b-5ca8a1c0"`
+	testLastModified = "Sun, 29 Aug 2021 05:26:00 GMT"
+)
+
+type conditionalSuite struct {
+	suite.Suite
+
+	transport *httpmock.MockTransport
+	requests  []http.Header
+	modified  bool
+}
+
+func (suite *conditionalSuite) SetupTest() {
+	suite.requests = nil
+	suite.modified = false
+
+	suite.transport = httpmock.NewMockTransport()
+	suite.transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/day",
+		func(req *http.Request) (*http.Response, error) {
+			suite.requests = append(suite.requests, req.Header)
+
+			if !suite.modified && req.Header.Get("If-None-Match") == testETag {
+				return newResponse(http.StatusNotModified, ""), nil
+			}
+
+			resp := newResponse(http.StatusOK, ExchangeRatePage)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			resp.Header.Add("ETag", testETag)
+			resp.Header.Add("Last-Modified", testLastModified)
+			return resp, nil
+		},
+	)
+}
+
+func (suite *conditionalSuite) newClient(opts ...twfxr.Option) *twfxr.Client {
+	return twfxr.NewClient(append(opts, twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}))...)
+}
+
+func (suite *conditionalSuite) TestWithoutCache() {
+	client := suite.newClient()
+
+	expected, metadata, err := client.GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.False(metadata.NotModified)
+	suite.Empty(suite.requests[0].Get("If-None-Match"))
+
+	// The rates reused from the last download are not affected by the changes of the caller.
+	delete(expected, twfxr.CurrencyUSD)
+
+	currencies, revalidated, err := client.GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.True(revalidated.NotModified)
+	suite.Equal(metadata.QuotedAt, revalidated.QuotedAt)
+	suite.Equal(metadata.SHA256, revalidated.SHA256)
+	suite.Equal(testETag, revalidated.Header.Get("ETag"))
+	suite.False(revalidated.FetchedAt.Before(metadata.FetchedAt))
+	suite.Len(currencies, 19)
+	suite.Equal(testETag, suite.requests[1].Get("If-None-Match"))
+	suite.Equal(testLastModified, suite.requests[1].Get("If-Modified-Since"))
+
+	suite.modified = true
+
+	currencies, metadata, err = client.GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.False(metadata.NotModified)
+	suite.Len(currencies, 19)
+	suite.Equal(3, suite.transport.GetTotalCallCount())
+}
+
+func (suite *conditionalSuite) TestWithCache() {
+	cache := twfxr.NewMemoryCache()
+	client := suite.newClient(twfxr.WithCache(cache), twfxr.WithCacheTTL(0, 0))
+
+	_, _, err := client.GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+
+	file, ok, err := cache.Get("https://rate.bot.com.tw/xrt/flcsv/0/day")
+	suite.NoError(err)
+	suite.True(ok)
+	suite.Equal(testETag, file.ETag)
+	suite.Equal(testLastModified, file.LastModified)
+
+	// A new client, e.g. in the next run of a batch job, revalidates the cached file.
+	currencies, metadata, err := suite.newClient(twfxr.WithCache(cache)).GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.True(metadata.NotModified)
+	suite.Len(currencies, 19)
+	suite.Equal(testETag, suite.requests[1].Get("If-None-Match"))
+
+	file, ok, err = cache.Get("https://rate.bot.com.tw/xrt/flcsv/0/day")
+	suite.NoError(err)
+	suite.True(ok)
+	suite.Equal(ExchangeRatePage, string(file.Data))
+	suite.False(file.ExpiresAt.IsZero())
+}
+
+func (suite *conditionalSuite) TestWithoutValidators() {
+	suite.transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/day",
+		func(req *http.Request) (*http.Response, error) {
+			suite.requests = append(suite.requests, req.Header)
+
+			resp := newResponse(http.StatusOK, ExchangeRatePage)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+
+	client := suite.newClient()
+
+	for i := 0; i < 2; i++ {
+		_, metadata, err := client.GetCurrencyExchangeRates(context.Background())
+		suite.NoError(err)
+		suite.False(metadata.NotModified)
+		suite.Empty(suite.requests[i].Get("If-None-Match"))
+		suite.Empty(suite.requests[i].Get("If-Modified-Since"))
+	}
+}
+
+func TestConditionalSuite(t *testing.T) {
+	suite.Run(t, new(conditionalSuite))
+}
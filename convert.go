@@ -0,0 +1,184 @@
+package twfxr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrRateUnavailable = errors.New("rate unavailable")
+)
+
+// Side is the side of a board rate, from the point of view of the bank.
+type Side int
+
+const (
+	// SideAuto picks the side by the direction of the trade: the bank buys the currency converted from and sells the
+	// currency converted to.
+	SideAuto    Side = iota
+	SideBuying       // 本行買入
+	SideSelling      // 本行賣出
+)
+
+func (s Side) String() string {
+	switch s {
+	case SideAuto:
+		return "auto"
+	case SideBuying:
+		return "buying"
+	case SideSelling:
+		return "selling"
+	default:
+		return fmt.Sprintf("Side(%d)", int(s))
+	}
+}
+
+// RateKind is the kind of a board rate.
+type RateKind int
+
+const (
+	RateKindSpot RateKind = iota // 即期
+	RateKindCash                 // 現金
+)
+
+func (k RateKind) String() string {
+	switch k {
+	case RateKindSpot:
+		return "spot"
+	case RateKindCash:
+		return "cash"
+	default:
+		return fmt.Sprintf("RateKind(%d)", int(k))
+	}
+}
+
+// RateUnavailableError is returned when the bank does not quote the requested rate, e.g. the cash rates of ZAR.
+type RateUnavailableError struct {
+	Currency Currency
+	Side     Side
+	Kind     RateKind
+}
+
+func (e *RateUnavailableError) Error() string {
+	return fmt.Sprintf("%s %s %s rate is not quoted", e.Currency, e.Side, e.Kind)
+}
+
+func (e *RateUnavailableError) Unwrap() error {
+	return ErrRateUnavailable
+}
+
+// Convert converts the amount of a currency into another currency with the given board rates. As all the board rates
+// are quoted against TWD, converting between two foreign currencies goes through TWD, e.g. USD is sold to the bank for
+// TWD which is then used to buy JPY.
+//
+// With SideAuto, the buying rate of the currency converted from and the selling rate of the currency converted to are
+// used. Otherwise, the given side is used for both currencies, e.g. to revaluate at the bank's buying rates.
+func Convert(rates map[Currency]CurrencyExchangeRate, amount float64, from, to Currency, side Side, kind RateKind) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	twd := amount
+
+	if from != CurrencyTWD {
+		rate, err := lookupRate(rates, from, resolveSide(side, SideBuying), kind)
+		if err != nil {
+			return 0, err
+		}
+		twd = amount * float64(rate)
+	}
+
+	if to == CurrencyTWD {
+		return twd, nil
+	}
+
+	rate, err := lookupRate(rates, to, resolveSide(side, SideSelling), kind)
+	if err != nil {
+		return 0, err
+	}
+
+	return twd / float64(rate), nil
+}
+
+// ConvertDecimal is like Convert but with exact decimal arithmetic. The result is rounded to the given number of
+// decimal places with the given rounding mode only once, after the conversion, e.g. 1,000 USD is exactly 27,845 TWD
+// at the spot buying rate of 27.845.
+func ConvertDecimal(rates map[Currency]CurrencyExchangeRate, amount Decimal, from, to Currency, side Side, kind RateKind, places int32, mode RoundingMode) (Decimal, error) {
+	if from == to {
+		return amount.Round(places, mode), nil
+	}
+
+	twd := amount
+
+	if from != CurrencyTWD {
+		d, err := lookupDecimal(rates, from, resolveSide(side, SideBuying), kind)
+		if err != nil {
+			return Decimal{}, err
+		}
+
+		twd = amount.Mul(d)
+	}
+
+	if to == CurrencyTWD {
+		return twd.Round(places, mode), nil
+	}
+
+	d, err := lookupDecimal(rates, to, resolveSide(side, SideSelling), kind)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return twd.Div(d, places, mode)
+}
+
+func resolveSide(side, auto Side) Side {
+	if side == SideAuto {
+		return auto
+	}
+	return side
+}
+
+func lookupRate(rates map[Currency]CurrencyExchangeRate, currency Currency, side Side, kind RateKind) (Rate, error) {
+	exchangeRate, ok := rates[currency]
+	if !ok {
+		return 0, fmt.Errorf("no such currency %s: %w", currency, ErrNotFound)
+	}
+
+	var rate float64
+
+	switch kind {
+	case RateKindSpot:
+		rate, ok = exchangeRate.Spot(side)
+	case RateKindCash:
+		rate, ok = exchangeRate.Cash(side)
+	}
+
+	if !ok {
+		return 0, &RateUnavailableError{Currency: currency, Side: side, Kind: kind}
+	}
+
+	return Rate(rate), nil
+}
+
+// lookupDecimal is like lookupRate but returns the rate as an exact decimal parsed from the rate file.
+func lookupDecimal(rates map[Currency]CurrencyExchangeRate, currency Currency, side Side, kind RateKind) (Decimal, error) {
+	exchangeRate, ok := rates[currency]
+	if !ok {
+		return Decimal{}, fmt.Errorf("no such currency %s: %w", currency, ErrNotFound)
+	}
+
+	var d Decimal
+
+	switch kind {
+	case RateKindSpot:
+		d, ok = exchangeRate.SpotDecimal(side)
+	case RateKindCash:
+		d, ok = exchangeRate.CashDecimal(side)
+	}
+
+	if !ok {
+		return Decimal{}, &RateUnavailableError{Currency: currency, Side: side, Kind: kind}
+	}
+
+	return d, nil
+}
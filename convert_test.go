@@ -0,0 +1,101 @@
+package twfxr_test
+
+import (
+	"testing"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	rates := map[twfxr.Currency]twfxr.CurrencyExchangeRate{
+		twfxr.CurrencyUSD: {
+			Currency:    "USD",
+			BuyingCash:  27.52000,
+			BuyingSpot:  27.84500,
+			SellingCash: 28.19000,
+			SellingSpot: 27.99500,
+		},
+		twfxr.CurrencyJPY: {
+			Currency:    "JPY",
+			BuyingCash:  0.24490,
+			BuyingSpot:  0.25190,
+			SellingCash: 0.25770,
+			SellingSpot: 0.25650,
+		},
+		twfxr.CurrencyZAR: {
+			Currency:    "ZAR",
+			BuyingSpot:  1.85100,
+			SellingSpot: 1.94100,
+		},
+	}
+
+	type args struct {
+		amount float64
+		from   twfxr.Currency
+		to     twfxr.Currency
+		side   twfxr.Side
+		kind   twfxr.RateKind
+	}
+
+	type wants struct {
+		amount float64
+		err    error
+	}
+
+	testCases := map[string]struct {
+		args  args
+		wants wants
+	}{
+		"When selling USD for TWD, Then the bank's buying rate is used": {
+			args:  args{amount: 1000, from: twfxr.CurrencyUSD, to: twfxr.CurrencyTWD, side: twfxr.SideAuto, kind: twfxr.RateKindSpot},
+			wants: wants{amount: 27845},
+		},
+		"When buying USD cash with TWD, Then the bank's selling rate is used": {
+			args:  args{amount: 28190, from: twfxr.CurrencyTWD, to: twfxr.CurrencyUSD, side: twfxr.SideAuto, kind: twfxr.RateKindCash},
+			wants: wants{amount: 1000},
+		},
+		"When converting USD to JPY, Then it goes through TWD": {
+			args:  args{amount: 100, from: twfxr.CurrencyUSD, to: twfxr.CurrencyJPY, side: twfxr.SideAuto, kind: twfxr.RateKindSpot},
+			wants: wants{amount: 100 * 27.845 / 0.2565},
+		},
+		"When the side is given, Then it is used for both currencies": {
+			args:  args{amount: 100, from: twfxr.CurrencyUSD, to: twfxr.CurrencyJPY, side: twfxr.SideBuying, kind: twfxr.RateKindSpot},
+			wants: wants{amount: 100 * 27.845 / 0.2519},
+		},
+		"When converting to the same currency, Then the amount is unchanged": {
+			args:  args{amount: 100, from: twfxr.CurrencyZAR, to: twfxr.CurrencyZAR, side: twfxr.SideAuto, kind: twfxr.RateKindCash},
+			wants: wants{amount: 100},
+		},
+		"When the rate is not quoted, Then it should return an error": {
+			args:  args{amount: 100, from: twfxr.CurrencyZAR, to: twfxr.CurrencyTWD, side: twfxr.SideAuto, kind: twfxr.RateKindCash},
+			wants: wants{err: twfxr.ErrRateUnavailable},
+		},
+		"When the currency is unknown, Then it should return an error": {
+			args:  args{amount: 100, from: twfxr.CurrencyTWD, to: twfxr.CurrencyEUR, side: twfxr.SideAuto, kind: twfxr.RateKindSpot},
+			wants: wants{err: twfxr.ErrNotFound},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			amount, err := twfxr.Convert(rates, tc.args.amount, tc.args.from, tc.args.to, tc.args.side, tc.args.kind)
+			if tc.wants.err != nil {
+				assert.ErrorIs(t, err, tc.wants.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.wants.amount, amount, 1e-9)
+		})
+	}
+
+	_, err := twfxr.Convert(rates, 100, twfxr.CurrencyZAR, twfxr.CurrencyTWD, twfxr.SideAuto, twfxr.RateKindCash)
+
+	var rateUnavailableErr *twfxr.RateUnavailableError
+	if assert.ErrorAs(t, err, &rateUnavailableErr) {
+		assert.Equal(t, twfxr.CurrencyZAR, rateUnavailableErr.Currency)
+		assert.Equal(t, twfxr.SideBuying, rateUnavailableErr.Side)
+		assert.Equal(t, twfxr.RateKindCash, rateUnavailableErr.Kind)
+	}
+}
@@ -1,5 +1,14 @@
 package twfxr
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownCurrency is returned by ParseCurrency when the currency is not in the registry.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
 type Currency string
 
 const (
@@ -20,30 +29,134 @@ const (
 	CurrencyEUR Currency = "EUR" // 歐元
 	CurrencyKRW Currency = "KRW" // 韓元
 	CurrencyVND Currency = "VND" // 越南盾
-	CurrencyMYR Currency = "MYR" // 越南盾
+	CurrencyMYR Currency = "MYR" // 馬來幣
 	CurrencyCNY Currency = "CNY" // 人民幣
 )
 
+// CurrencyTWD is the base currency all the board rates are quoted against.
+const CurrencyTWD Currency = "TWD" // 新台幣
+
+// currencyInfo is the entry of a currency in the registry.
+type currencyInfo struct {
+	nameZhTW string
+	nameEn   string
+	// aliases are the other names the currency is commonly called in Taiwan.
+	aliases []string
+	// numeric is the ISO 4217 numeric code.
+	numeric int
+	// minorUnits is the number of decimal places defined by ISO 4217.
+	minorUnits int
+}
+
+// currencyRegistry is the registry of TWD and the currencies quoted by the bank.
+var currencyRegistry = map[Currency]currencyInfo{
+	CurrencyUSD: {nameZhTW: "美金", nameEn: "US Dollar", aliases: []string{"美元"}, numeric: 840, minorUnits: 2},
+	CurrencyHKD: {nameZhTW: "港幣", nameEn: "Hong Kong Dollar", aliases: []string{"港元"}, numeric: 344, minorUnits: 2},
+	CurrencyGBP: {nameZhTW: "英鎊", nameEn: "Pound Sterling", numeric: 826, minorUnits: 2},
+	CurrencyAUD: {nameZhTW: "澳幣", nameEn: "Australian Dollar", aliases: []string{"澳元"}, numeric: 36, minorUnits: 2},
+	CurrencyCAD: {nameZhTW: "加拿大幣", nameEn: "Canadian Dollar", aliases: []string{"加幣"}, numeric: 124, minorUnits: 2},
+	CurrencySGD: {nameZhTW: "新加坡幣", nameEn: "Singapore Dollar", aliases: []string{"新幣"}, numeric: 702, minorUnits: 2},
+	CurrencyCHF: {nameZhTW: "瑞士法郎", nameEn: "Swiss Franc", numeric: 756, minorUnits: 2},
+	CurrencyJPY: {nameZhTW: "日圓", nameEn: "Japanese Yen", aliases: []string{"日幣", "日元"}, numeric: 392, minorUnits: 0},
+	CurrencyZAR: {nameZhTW: "南非幣", nameEn: "South African Rand", numeric: 710, minorUnits: 2},
+	CurrencySEK: {nameZhTW: "瑞典幣", nameEn: "Swedish Krona", numeric: 752, minorUnits: 2},
+	CurrencyNZD: {nameZhTW: "紐元", nameEn: "New Zealand Dollar", aliases: []string{"紐幣"}, numeric: 554, minorUnits: 2},
+	CurrencyTHB: {nameZhTW: "泰幣", nameEn: "Thai Baht", aliases: []string{"泰銖"}, numeric: 764, minorUnits: 2},
+	CurrencyPHP: {nameZhTW: "菲國比索", nameEn: "Philippine Peso", aliases: []string{"菲律賓比索"}, numeric: 608, minorUnits: 2},
+	CurrencyIDR: {nameZhTW: "印尼幣", nameEn: "Indonesian Rupiah", aliases: []string{"印尼盾"}, numeric: 360, minorUnits: 2},
+	CurrencyEUR: {nameZhTW: "歐元", nameEn: "Euro", numeric: 978, minorUnits: 2},
+	CurrencyKRW: {nameZhTW: "韓元", nameEn: "South Korean Won", aliases: []string{"韓圜", "韓幣"}, numeric: 410, minorUnits: 0},
+	CurrencyVND: {nameZhTW: "越南盾", nameEn: "Vietnamese Dong", numeric: 704, minorUnits: 0},
+	CurrencyMYR: {nameZhTW: "馬來幣", nameEn: "Malaysian Ringgit", aliases: []string{"令吉"}, numeric: 458, minorUnits: 2},
+	CurrencyCNY: {nameZhTW: "人民幣", nameEn: "Chinese Yuan Renminbi", numeric: 156, minorUnits: 2},
+	CurrencyTWD: {nameZhTW: "新台幣", nameEn: "New Taiwan Dollar", aliases: []string{"新臺幣", "台幣", "臺幣"}, numeric: 901, minorUnits: 2},
+}
+
+// AllCurrencies returns the currencies quoted by the bank in the order of the board, which excludes TWD.
+func AllCurrencies() []Currency {
+	return []Currency{
+		CurrencyUSD, CurrencyHKD, CurrencyGBP, CurrencyAUD, CurrencyCAD, CurrencySGD, CurrencyCHF, CurrencyJPY,
+		CurrencyZAR, CurrencySEK, CurrencyNZD, CurrencyTHB, CurrencyPHP, CurrencyIDR, CurrencyEUR, CurrencyKRW,
+		CurrencyVND, CurrencyMYR, CurrencyCNY,
+	}
+}
+
+// ParseCurrency parses a currency code case-insensitively, e.g. usd, or a name of the currency in zh-TW or English,
+// e.g. 美金, 日幣 or Japanese Yen. An error wrapping ErrUnknownCurrency is returned for a currency not in the registry.
+func ParseCurrency(s string) (Currency, error) {
+	name := strings.TrimSpace(s)
+
+	if currency := Currency(strings.ToUpper(name)); currency.Valid() {
+		return currency, nil
+	}
+
+	for currency, info := range currencyRegistry {
+		if name == info.nameZhTW || strings.EqualFold(name, info.nameEn) {
+			return currency, nil
+		}
+		for _, alias := range info.aliases {
+			if name == alias {
+				return currency, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w %q", ErrUnknownCurrency, s)
+}
+
+// Valid reports whether the currency is in the registry, i.e. TWD or a currency quoted by the bank.
+func (c Currency) Valid() bool {
+	_, ok := currencyRegistry[c]
+	return ok
+}
+
+// Name returns the name of the currency in the given language, either LanguageZhTW or LanguageEn. The other languages
+// fall back to English. It returns an empty string for a currency not in the registry.
+func (c Currency) Name(lang string) string {
+	info := currencyRegistry[c]
+	if lang == LanguageZhTW {
+		return info.nameZhTW
+	}
+	return info.nameEn
+}
+
+// Numeric returns the ISO 4217 numeric code of the currency, e.g. 840 for USD, or 0 for a currency not in the
+// registry.
+func (c Currency) Numeric() int {
+	return currencyRegistry[c].numeric
+}
+
+// MinorUnits returns the number of decimal places of the currency defined by ISO 4217, e.g. 2 for USD and 0 for JPY.
+// It returns 0 for a currency not in the registry, use Valid to tell it apart.
+func (c Currency) MinorUnits() int {
+	return currencyRegistry[c].minorUnits
+}
+
 type CurrencyExchangeRate struct {
-	Currency string `json:"Currency"`
+	Currency Currency `json:"Currency"`
 	// 本行買入
-	BuyingCash           float64 `json:"Buying-Cash"` // 現金匯率
-	BuyingSpot           float64 `json:"Buying-Spot"` // 即期匯率
-	BuyingForward10Days  float64 `json:"Buying-Forward-10Days"`
-	BuyingForward30Days  float64 `json:"Buying-Forward-30Days"`
-	BuyingForward60Days  float64 `json:"Buying-Forward-60Days"`
-	BuyingForward90Days  float64 `json:"Buying-Forward-90Days"`
-	BuyingForward120Days float64 `json:"Buying-Forward-120Days"`
-	BuyingForward150Days float64 `json:"Buying-Forward-150Days"`
-	BuyingForward180Days float64 `json:"Buying-Forward-180Days"`
+	BuyingCash           Rate `json:"Buying-Cash"` // 現金匯率
+	BuyingSpot           Rate `json:"Buying-Spot"` // 即期匯率
+	BuyingForward10Days  Rate `json:"Buying-Forward-10Days"`
+	BuyingForward30Days  Rate `json:"Buying-Forward-30Days"`
+	BuyingForward60Days  Rate `json:"Buying-Forward-60Days"`
+	BuyingForward90Days  Rate `json:"Buying-Forward-90Days"`
+	BuyingForward120Days Rate `json:"Buying-Forward-120Days"`
+	BuyingForward150Days Rate `json:"Buying-Forward-150Days"`
+	BuyingForward180Days Rate `json:"Buying-Forward-180Days"`
 	// 本行賣出
-	SellingCash           float64 `json:"Selling-Cash"` // 現金匯率
-	SellingSpot           float64 `json:"Selling-Spot"` // 即期匯率
-	SellingForward10Days  float64 `json:"Selling-Forward-10Days"`
-	SellingForward30Days  float64 `json:"Selling-Forward-30Days"`
-	SellingForward60Days  float64 `json:"Selling-Forward-60Days"`
-	SellingForward90Days  float64 `json:"Selling-Forward-90Days"`
-	SellingForward120Days float64 `json:"Selling-Forward-120Days"`
-	SellingForward150Days float64 `json:"Selling-Forward-150Days"`
-	SellingForward180Days float64 `json:"Selling-Forward-180Days"`
+	SellingCash           Rate `json:"Selling-Cash"` // 現金匯率
+	SellingSpot           Rate `json:"Selling-Spot"` // 即期匯率
+	SellingForward10Days  Rate `json:"Selling-Forward-10Days"`
+	SellingForward30Days  Rate `json:"Selling-Forward-30Days"`
+	SellingForward60Days  Rate `json:"Selling-Forward-60Days"`
+	SellingForward90Days  Rate `json:"Selling-Forward-90Days"`
+	SellingForward120Days Rate `json:"Selling-Forward-120Days"`
+	SellingForward150Days Rate `json:"Selling-Forward-150Days"`
+	SellingForward180Days Rate `json:"Selling-Forward-180Days"`
+
+	// texts are the rates as written in the rate file, by side and in the order of rateHeaders, used by the decimal
+	// accessors. A text is only kept when it differs from the rate formatted with the bank's 5 decimal places, which
+	// is the case for none of the bank's rates, so that parsed rates still compare equal to literals.
+	texts [2][9]string
 }
@@ -0,0 +1,85 @@
+package twfxr_test
+
+import (
+	"testing"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCurrency(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		want  twfxr.Currency
+		err   bool
+	}{
+		"code":               {input: "USD", want: twfxr.CurrencyUSD},
+		"lower case code":    {input: "jpy", want: twfxr.CurrencyJPY},
+		"surrounding spaces": {input: " eur ", want: twfxr.CurrencyEUR},
+		"TWD":                {input: "twd", want: twfxr.CurrencyTWD},
+		"chinese name":       {input: "美金", want: twfxr.CurrencyUSD},
+		"chinese alias":      {input: "日幣", want: twfxr.CurrencyJPY},
+		"traditional alias":  {input: "新臺幣", want: twfxr.CurrencyTWD},
+		"english name":       {input: "swiss franc", want: twfxr.CurrencyCHF},
+		"unknown code":       {input: "XYZ", err: true},
+		"unknown name":       {input: "比特幣", err: true},
+		"empty":              {input: "", err: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			currency, err := twfxr.ParseCurrency(tc.input)
+			if tc.err {
+				assert.ErrorIs(t, err, twfxr.ErrUnknownCurrency)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, currency)
+		})
+	}
+}
+
+func TestCurrencyRegistry(t *testing.T) {
+	testCases := map[string]struct {
+		currency   twfxr.Currency
+		valid      bool
+		nameZhTW   string
+		nameEn     string
+		numeric    int
+		minorUnits int
+	}{
+		"USD":     {currency: twfxr.CurrencyUSD, valid: true, nameZhTW: "美金", nameEn: "US Dollar", numeric: 840, minorUnits: 2},
+		"JPY":     {currency: twfxr.CurrencyJPY, valid: true, nameZhTW: "日圓", nameEn: "Japanese Yen", numeric: 392},
+		"AUD":     {currency: twfxr.CurrencyAUD, valid: true, nameZhTW: "澳幣", nameEn: "Australian Dollar", numeric: 36, minorUnits: 2},
+		"TWD":     {currency: twfxr.CurrencyTWD, valid: true, nameZhTW: "新台幣", nameEn: "New Taiwan Dollar", numeric: 901, minorUnits: 2},
+		"unknown": {currency: "XYZ"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.valid, tc.currency.Valid())
+			assert.Equal(t, tc.nameZhTW, tc.currency.Name(twfxr.LanguageZhTW))
+			assert.Equal(t, tc.nameEn, tc.currency.Name(twfxr.LanguageEn))
+			assert.Equal(t, tc.nameEn, tc.currency.Name("ja"))
+			assert.Equal(t, tc.numeric, tc.currency.Numeric())
+			assert.Equal(t, tc.minorUnits, tc.currency.MinorUnits())
+		})
+	}
+}
+
+func TestAllCurrencies(t *testing.T) {
+	currencies := twfxr.AllCurrencies()
+
+	assert.Len(t, currencies, 19)
+	assert.Equal(t, twfxr.CurrencyUSD, currencies[0])
+	assert.Equal(t, twfxr.CurrencyCNY, currencies[len(currencies)-1])
+	assert.NotContains(t, currencies, twfxr.CurrencyTWD)
+
+	for _, currency := range currencies {
+		assert.True(t, currency.Valid(), currency)
+	}
+
+	// The result is a copy which can be modified by the caller.
+	currencies[0] = twfxr.CurrencyTWD
+	assert.Equal(t, twfxr.CurrencyUSD, twfxr.AllCurrencies()[0])
+}
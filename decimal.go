@@ -0,0 +1,336 @@
+package twfxr
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrDivisionByZero = errors.New("division by zero")
+)
+
+// RoundingMode is the way a Decimal is rounded when digits are dropped.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest neighbour, and away from zero when both neighbours are equidistant.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest neighbour, and to the even neighbour when both neighbours are equidistant,
+	// a.k.a. banker's rounding.
+	RoundHalfEven
+	// RoundDown rounds towards zero, i.e. truncates.
+	RoundDown
+)
+
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "half-up"
+	case RoundHalfEven:
+		return "half-even"
+	case RoundDown:
+		return "down"
+	default:
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+}
+
+// Decimal is an exact decimal number, i.e. an arbitrary-precision integer scaled by a power of ten. The zero value is
+// 0. Decimals are immutable, every operation returns a new Decimal.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// NewDecimal returns the decimal value × 10^-scale, e.g. NewDecimal(27845, 3) is 27.845.
+func NewDecimal(value int64, scale int32) Decimal {
+	return Decimal{unscaled: big.NewInt(value), scale: scale}
+}
+
+// ParseDecimal parses a decimal in the form of [-+]digits[.digits], keeping every digit including the trailing zeros,
+// e.g. "27.84500" has 5 decimal places.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	var scale int32
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		scale = int32(len(digits) - i - 1)
+		digits = digits[:i] + digits[i+1:]
+	}
+
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	unscaled, _ := new(big.Int).SetString(digits, 10)
+	if strings.HasPrefix(s, "-") {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{unscaled: unscaled, scale: scale}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s cannot be parsed.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale returns the number of decimal places.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// rescale returns the unscaled value of d with the given scale, which must not be less than the scale of d.
+func (d Decimal) rescale(scale int32) *big.Int {
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+// Cmp compares d and y and returns -1, 0 or +1 if d is less than, equal to or greater than y.
+func (d Decimal) Cmp(y Decimal) int {
+	scale := maxScale(d, y)
+	return d.rescale(scale).Cmp(y.rescale(scale))
+}
+
+// Equal reports whether d and y are the same number regardless of their scales.
+func (d Decimal) Equal(y Decimal) bool {
+	return d.Cmp(y) == 0
+}
+
+// Add returns d + y.
+func (d Decimal) Add(y Decimal) Decimal {
+	scale := maxScale(d, y)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), y.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - y.
+func (d Decimal) Sub(y Decimal) Decimal {
+	scale := maxScale(d, y)
+	return Decimal{unscaled: new(big.Int).Sub(d.rescale(scale), y.rescale(scale)), scale: scale}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Mul returns d × y exactly.
+func (d Decimal) Mul(y Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), y.int()), scale: d.scale + y.scale}
+}
+
+// Div returns d ÷ y rounded to the given number of decimal places.
+func (d Decimal) Div(y Decimal, places int32, mode RoundingMode) (Decimal, error) {
+	if y.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	// d ÷ y × 10^places = d.unscaled × 10^(places + y.scale - d.scale) ÷ y.unscaled
+	num, den := new(big.Int).Set(d.int()), new(big.Int).Set(y.int())
+	if exp := places + y.scale - d.scale; exp >= 0 {
+		num.Mul(num, pow10(exp))
+	} else {
+		den.Mul(den, pow10(-exp))
+	}
+
+	return Decimal{unscaled: roundQuo(num, den, mode), scale: places}, nil
+}
+
+// Round returns d rounded to the given number of decimal places. The result always has exactly that many decimal
+// places, e.g. 27.8 rounded to 2 places is 27.80.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places >= d.scale {
+		return Decimal{unscaled: d.rescale(places), scale: places}
+	}
+
+	return Decimal{unscaled: roundQuo(d.int(), pow10(d.scale-places), mode), scale: places}
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d with all of its decimal places, e.g. 27.84500.
+func (d Decimal) String() string {
+	if d.scale <= 0 {
+		return d.rescale(0).String()
+	}
+
+	digits := new(big.Int).Abs(d.int()).String()
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+
+	point := len(digits) - int(d.scale)
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(b []byte) error {
+	v, err := ParseDecimal(string(b))
+	if err != nil {
+		return err
+	}
+
+	*d = v
+
+	return nil
+}
+
+// MarshalJSON encodes d as a JSON number keeping all of its decimal places.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return d.MarshalText()
+}
+
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	return d.UnmarshalText([]byte(strings.Trim(string(b), `"`)))
+}
+
+// RoundTWD rounds an amount of TWD to its minor unit.
+func RoundTWD(d Decimal, mode RoundingMode) Decimal {
+	return d.Round(int32(CurrencyTWD.MinorUnits()), mode)
+}
+
+// rateScale is the number of decimal places of the rates quoted by the bank, e.g. 27.84500.
+const rateScale = 5
+
+// Decimal returns the rate as an exact decimal with the bank's 5 decimal places, e.g. 27.84500, and whether it is
+// offered by the bank. Prefer the decimal accessors of CurrencyExchangeRate, which keep the text of the rate file.
+//
+// The bank quotes rates with 5 decimal places, which float64 represents without ambiguity, so the shortest decimal
+// representation of the rate is exactly the quoted digits, e.g. 27.845 rather than 27.844999999999998863. A rate with
+// more decimal places keeps all of them.
+func (r Rate) Decimal() (Decimal, bool) {
+	if !r.Valid() {
+		return Decimal{}, false
+	}
+
+	d, err := ParseDecimal(strconv.FormatFloat(float64(r), 'f', -1, 64))
+	if err != nil {
+		return Decimal{}, false
+	}
+
+	if d.scale < rateScale {
+		d = d.Round(rateScale, RoundDown)
+	}
+
+	return d, true
+}
+
+// SpotDecimal is like Spot but returns the rate as an exact decimal parsed from the rate file, e.g. 27.84500.
+func (r CurrencyExchangeRate) SpotDecimal(side Side) (Decimal, bool) {
+	return r.decimal(side, 1)
+}
+
+// CashDecimal is like Cash but returns the rate as an exact decimal parsed from the rate file, e.g. 27.52000.
+func (r CurrencyExchangeRate) CashDecimal(side Side) (Decimal, bool) {
+	return r.decimal(side, 0)
+}
+
+// ForwardDecimal returns the forward rate of the given side and tenor as an exact decimal parsed from the rate file,
+// and whether it is offered by the bank.
+func (r CurrencyExchangeRate) ForwardDecimal(side Side, tenor Tenor) (Decimal, bool) {
+	for i, t := range Tenors() {
+		if t == tenor {
+			return r.decimal(side, i+2)
+		}
+	}
+	return Decimal{}, false
+}
+
+// decimal returns the i-th rate of the given side in the order of rateHeaders as an exact decimal, parsed from the
+// text of the rate file if it is kept.
+func (r CurrencyExchangeRate) decimal(side Side, i int) (Decimal, bool) {
+	rates := r.rates(side)
+	if rates == nil || !rates[i].Valid() {
+		return Decimal{}, false
+	}
+
+	if text := r.texts[side-SideBuying][i]; text != "" {
+		d, err := ParseDecimal(text)
+		return d, err == nil
+	}
+
+	return rates[i].Decimal()
+}
+
+// setText keeps the text of the i-th rate of the given side in the order of rateHeaders, unless the rate formatted
+// with the bank's 5 decimal places reads the same.
+func (r *CurrencyExchangeRate) setText(side Side, i int, text string) {
+	rate := *r.rates(side)[i]
+	if d, ok := rate.Decimal(); !ok || d.String() == text {
+		text = ""
+	}
+	r.texts[side-SideBuying][i] = text
+}
+
+// roundQuo returns num ÷ den rounded to an integer.
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 || mode == RoundDown {
+		return quo
+	}
+
+	// Compare the dropped fraction |rem ÷ den| with a half.
+	half := new(big.Int).Abs(rem)
+	half.Lsh(half, 1)
+	cmp := half.Cmp(new(big.Int).Abs(den))
+
+	if cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1)) {
+		if num.Sign()*den.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	return quo
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func maxScale(x, y Decimal) int32 {
+	if x.scale > y.scale {
+		return x.scale
+	}
+	return y.scale
+}
@@ -0,0 +1,164 @@
+package twfxr_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		want  string
+		err   bool
+	}{
+		"keeps trailing zeros":   {input: "27.84500", want: "27.84500"},
+		"integer":                {input: "1000", want: "1000"},
+		"negative":               {input: "-0.00158", want: "-0.00158"},
+		"leading point":          {input: ".5", want: "0.5"},
+		"empty":                  {input: "", err: true},
+		"dash for missing quote": {input: "-", err: true},
+		"exponent":               {input: "1e5", err: true},
+		"two signs":              {input: "+-1", err: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d, err := twfxr.ParseDecimal(tc.input)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, d.String())
+		})
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	rate := twfxr.MustParseDecimal("27.845")
+
+	assert.Equal(t, "27845.000", rate.Mul(twfxr.NewDecimal(1000, 0)).String())
+	assert.True(t, rate.Mul(twfxr.NewDecimal(1000, 0)).Equal(twfxr.NewDecimal(27845, 0)))
+	assert.Equal(t, "27.995", rate.Add(twfxr.MustParseDecimal("0.15")).String())
+	assert.Equal(t, "-0.150", rate.Sub(twfxr.MustParseDecimal("27.995")).String())
+	assert.Equal(t, 1, rate.Cmp(twfxr.MustParseDecimal("27.8449")))
+
+	q, err := twfxr.NewDecimal(28190, 0).Div(twfxr.MustParseDecimal("28.19"), 2, twfxr.RoundHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "1000.00", q.String())
+
+	_, err = rate.Div(twfxr.Decimal{}, 2, twfxr.RoundHalfUp)
+	assert.ErrorIs(t, err, twfxr.ErrDivisionByZero)
+}
+
+func TestDecimalRound(t *testing.T) {
+	testCases := map[string]struct {
+		input  string
+		places int32
+		mode   twfxr.RoundingMode
+		want   string
+	}{
+		"half-up rounds half away from zero":          {input: "2.5", places: 0, mode: twfxr.RoundHalfUp, want: "3"},
+		"half-up rounds negative half away from zero": {input: "-2.5", places: 0, mode: twfxr.RoundHalfUp, want: "-3"},
+		"half-even rounds half to even down":          {input: "2.5", places: 0, mode: twfxr.RoundHalfEven, want: "2"},
+		"half-even rounds half to even up":            {input: "3.5", places: 0, mode: twfxr.RoundHalfEven, want: "4"},
+		"half-even rounds above half up":              {input: "2.51", places: 0, mode: twfxr.RoundHalfEven, want: "3"},
+		"down truncates":                              {input: "-2.99", places: 1, mode: twfxr.RoundDown, want: "-2.9"},
+		"pads to the number of places":                {input: "27.8", places: 2, mode: twfxr.RoundHalfUp, want: "27.80"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, twfxr.MustParseDecimal(tc.input).Round(tc.places, tc.mode).String())
+		})
+	}
+
+	assert.Equal(t, "27845.13", twfxr.RoundTWD(twfxr.MustParseDecimal("27845.125"), twfxr.RoundHalfUp).String())
+	assert.Equal(t, "27845.12", twfxr.RoundTWD(twfxr.MustParseDecimal("27845.125"), twfxr.RoundHalfEven).String())
+}
+
+func TestDecimalJSON(t *testing.T) {
+	b, err := json.Marshal(twfxr.MustParseDecimal("0.00158"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00158", string(b))
+
+	var d twfxr.Decimal
+	assert.NoError(t, json.Unmarshal([]byte("27.84500"), &d))
+	assert.Equal(t, "27.84500", d.String())
+}
+
+func TestRateDecimal(t *testing.T) {
+	d, ok := twfxr.Rate(27.845).Decimal()
+	assert.True(t, ok)
+	assert.Equal(t, "27.84500", d.String())
+
+	d, ok = twfxr.Rate(0.00158).Decimal()
+	assert.True(t, ok)
+	assert.Equal(t, "0.00158", d.String())
+
+	_, ok = twfxr.Rate(0).Decimal()
+	assert.False(t, ok)
+}
+
+func TestExchangeRateDecimals(t *testing.T) {
+	rates, _, err := twfxr.ParseFile("testdata/ExchangeRate@202108290526.csv")
+	require.NoError(t, err)
+
+	usd := rates[twfxr.CurrencyUSD]
+
+	d, ok := usd.SpotDecimal(twfxr.SideBuying)
+	assert.True(t, ok)
+	assert.Equal(t, "27.84500", d.String())
+	assert.Equal(t, "27845.00000", d.Mul(twfxr.NewDecimal(1000, 0)).String())
+
+	d, ok = usd.CashDecimal(twfxr.SideSelling)
+	assert.True(t, ok)
+	assert.Equal(t, "28.19000", d.String())
+
+	d, ok = usd.ForwardDecimal(twfxr.SideBuying, twfxr.Tenor180Days)
+	assert.True(t, ok)
+	assert.Equal(t, usd.BuyingForward180Days, twfxr.Rate(d.Float64()))
+	assert.EqualValues(t, 5, d.Scale())
+
+	_, ok = rates[twfxr.CurrencyZAR].CashDecimal(twfxr.SideBuying)
+	assert.False(t, ok)
+
+	_, ok = usd.ForwardDecimal(twfxr.SideBuying, twfxr.Tenor(45))
+	assert.False(t, ok)
+
+	// A rate written with fewer decimal places than the bank's keeps its own.
+	var r twfxr.CurrencyExchangeRate
+	require.NoError(t, r.UnmarshalText([]byte("USD,本行買入,27.52,27.845,0,0,0,0,0,0,0,本行賣出,28.19,27.995,0,0,0,0,0,0,0")))
+
+	d, ok = r.SpotDecimal(twfxr.SideBuying)
+	assert.True(t, ok)
+	assert.Equal(t, "27.845", d.String())
+	assert.Equal(t, "USD,本行買入,27.52,27.845,0.00000,0.00000,0.00000,0.00000,0.00000,0.00000,0.00000,本行賣出,28.19,27.995,0.00000,0.00000,0.00000,0.00000,0.00000,0.00000,0.00000", r.String())
+}
+
+func TestConvertDecimal(t *testing.T) {
+	rates := map[twfxr.Currency]twfxr.CurrencyExchangeRate{
+		twfxr.CurrencyUSD: {Currency: "USD", BuyingSpot: 27.845, SellingSpot: 27.995},
+		twfxr.CurrencyJPY: {Currency: "JPY", BuyingSpot: 0.2519, SellingSpot: 0.2565},
+	}
+
+	amount, err := twfxr.ConvertDecimal(rates, twfxr.NewDecimal(1000, 0), twfxr.CurrencyUSD, twfxr.CurrencyTWD,
+		twfxr.SideAuto, twfxr.RateKindSpot, 0, twfxr.RoundHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "27845", amount.String())
+
+	// 100 × 27.845 ÷ 0.2565 = 10855.750487...
+	amount, err = twfxr.ConvertDecimal(rates, twfxr.NewDecimal(100, 0), twfxr.CurrencyUSD, twfxr.CurrencyJPY,
+		twfxr.SideAuto, twfxr.RateKindSpot, 2, twfxr.RoundHalfEven)
+	assert.NoError(t, err)
+	assert.Equal(t, "10855.75", amount.String())
+
+	_, err = twfxr.ConvertDecimal(rates, twfxr.NewDecimal(100, 0), twfxr.CurrencyUSD, twfxr.CurrencyTWD,
+		twfxr.SideAuto, twfxr.RateKindCash, 0, twfxr.RoundHalfUp)
+	assert.ErrorIs(t, err, twfxr.ErrRateUnavailable)
+}
@@ -0,0 +1,346 @@
+package twfxr
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+var (
+	// dailyMapping is the column mapping of a record of the daily rate file, used to decode a CurrencyExchangeRate.
+	dailyMapping = mustColumnMapping(dailyHeader(LanguageZhTW))
+	// historyMapping is the column mapping of a record of the history file, used to decode a DatedExchangeRate.
+	historyMapping = mustColumnMapping(append([]string{headerDate}, dailyHeader(LanguageZhTW)...))
+)
+
+// String returns the currency code.
+func (c Currency) String() string {
+	return string(c)
+}
+
+// MarshalText encodes the currency as its code.
+func (c Currency) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+// UnmarshalText decodes a currency by ParseCurrency. A code not in the registry is kept as it is when it is accepted
+// by the parser of the rate files, i.e. 3 upper case letters, so that a currency newly listed by the bank survives a
+// round trip. An empty text is decoded as the zero Currency.
+func (c *Currency) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*c = ""
+		return nil
+	}
+
+	currency, err := ParseCurrency(string(b))
+	if err != nil {
+		if !currencyPattern.Match(b) {
+			return err
+		}
+		currency = Currency(b)
+	}
+
+	*c = currency
+
+	return nil
+}
+
+// Set implements flag.Value and pflag.Value as UnmarshalText, rejecting a currency not in the registry.
+func (c *Currency) Set(s string) error {
+	var currency Currency
+	if err := currency.UnmarshalText([]byte(s)); err != nil {
+		return err
+	}
+
+	if currency != "" && !currency.Valid() {
+		return fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+
+	*c = currency
+
+	return nil
+}
+
+// Type implements pflag.Value.
+func (c *Currency) Type() string {
+	return "currency"
+}
+
+// Scan implements sql.Scanner, decoding a string as UnmarshalText, so that whatever Value stores is read back, and
+// NULL as the zero Currency.
+func (c *Currency) Scan(src interface{}) error {
+	b, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return c.UnmarshalText(b)
+}
+
+// Value implements driver.Valuer, encoding the currency as its code and the zero Currency as NULL.
+func (c Currency) Value() (driver.Value, error) {
+	if c == "" {
+		return nil, nil
+	}
+	return string(c), nil
+}
+
+// exchangeRateJSON is CurrencyExchangeRate without its methods, so that it is encoded as an object in JSON instead of
+// by MarshalText.
+type exchangeRateJSON CurrencyExchangeRate
+
+// record returns the fields of the rates in the format of a record of the daily rate file of the bank in the given
+// language, without the trailing empty field.
+func (r CurrencyExchangeRate) record(lang string) []string {
+	record := []string{string(r.Currency)}
+
+	for _, side := range []struct {
+		side   Side
+		marker string
+	}{{SideBuying, markerBuying}, {SideSelling, markerSelling}} {
+		record = append(record, localizeHeader(side.marker, lang))
+		for i, rate := range r.rates(side.side) {
+			if d, ok := r.decimal(side.side, i); ok {
+				record = append(record, d.String())
+			} else {
+				record = append(record, strconv.FormatFloat(float64(*rate), 'f', rateScale, 64))
+			}
+		}
+	}
+
+	return record
+}
+
+// String returns the rates encoded by MarshalText.
+func (r CurrencyExchangeRate) String() string {
+	b, _ := r.MarshalText()
+	return string(b)
+}
+
+// MarshalText encodes the rates as a record of the daily rate file of the bank, e.g.
+// "USD,本行買入,27.52000,27.84500,...,本行賣出,28.19000,27.99500,...".
+func (r CurrencyExchangeRate) MarshalText() ([]byte, error) {
+	return encodeRecord(r.record(LanguageZhTW))
+}
+
+// UnmarshalText decodes a record of the daily rate file of the bank in either language, accepting the same currencies
+// as the parser of the rate files.
+func (r *CurrencyExchangeRate) UnmarshalText(b []byte) error {
+	record, err := decodeRecord(b)
+	if err != nil {
+		return err
+	}
+
+	exchangeRate, err := dailyMapping.parse(1, record)
+	if err != nil {
+		return err
+	}
+
+	*r = exchangeRate
+
+	return nil
+}
+
+// MarshalJSON encodes the rates as an object keyed by the JSON tags of the fields.
+func (r CurrencyExchangeRate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(exchangeRateJSON(r))
+}
+
+// UnmarshalJSON decodes an object encoded by MarshalJSON.
+func (r *CurrencyExchangeRate) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, (*exchangeRateJSON)(r))
+}
+
+// Set implements flag.Value and pflag.Value as UnmarshalText, rejecting a currency not in the registry.
+func (r *CurrencyExchangeRate) Set(s string) error {
+	var exchangeRate CurrencyExchangeRate
+	if err := exchangeRate.UnmarshalText([]byte(s)); err != nil {
+		return err
+	}
+
+	if !exchangeRate.Currency.Valid() {
+		return fmt.Errorf("%w %q", ErrUnknownCurrency, exchangeRate.Currency)
+	}
+
+	*r = exchangeRate
+
+	return nil
+}
+
+// Type implements pflag.Value.
+func (r *CurrencyExchangeRate) Type() string {
+	return "exchange-rate"
+}
+
+// Scan implements sql.Scanner, decoding a string as UnmarshalText, so that whatever Value stores is read back, and
+// NULL as the zero CurrencyExchangeRate.
+func (r *CurrencyExchangeRate) Scan(src interface{}) error {
+	if src == nil {
+		*r = CurrencyExchangeRate{}
+		return nil
+	}
+
+	b, err := scanText(src)
+	if err != nil {
+		return err
+	}
+
+	return r.UnmarshalText(b)
+}
+
+// Value implements driver.Valuer, encoding the rates as MarshalText.
+func (r CurrencyExchangeRate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// datedExchangeRateJSON is DatedExchangeRate without its methods, so that it is encoded as an object in JSON with the
+// fields of the rates inlined.
+type datedExchangeRateJSON struct {
+	Date time.Time
+	exchangeRateJSON
+}
+
+// String returns the rates encoded by MarshalText.
+func (r DatedExchangeRate) String() string {
+	b, _ := r.MarshalText()
+	return string(b)
+}
+
+// MarshalText encodes the rates as a record of the history file of the bank, i.e. the date followed by a record of
+// the daily rate file, e.g. "20210827,USD,本行買入,27.52000,...".
+func (r DatedExchangeRate) MarshalText() ([]byte, error) {
+	return encodeRecord(append([]string{r.Date.Format(historyDateLayout)}, r.record(LanguageZhTW)...))
+}
+
+// UnmarshalText decodes a record of the history file of the bank in either language, accepting the same currencies as
+// the parser of the rate files.
+func (r *DatedExchangeRate) UnmarshalText(b []byte) error {
+	record, err := decodeRecord(b)
+	if err != nil {
+		return err
+	}
+
+	exchangeRate, err := historyMapping.parseDated(1, record)
+	if err != nil {
+		return err
+	}
+
+	*r = exchangeRate
+
+	return nil
+}
+
+// MarshalJSON encodes the rates as an object of the date and the fields of CurrencyExchangeRate.
+func (r DatedExchangeRate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(datedExchangeRateJSON{Date: r.Date, exchangeRateJSON: exchangeRateJSON(r.CurrencyExchangeRate)})
+}
+
+// UnmarshalJSON decodes an object encoded by MarshalJSON.
+func (r *DatedExchangeRate) UnmarshalJSON(b []byte) error {
+	var v datedExchangeRateJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	*r = DatedExchangeRate{Date: v.Date, CurrencyExchangeRate: CurrencyExchangeRate(v.exchangeRateJSON)}
+
+	return nil
+}
+
+// Set implements flag.Value and pflag.Value as UnmarshalText, rejecting a currency not in the registry.
+func (r *DatedExchangeRate) Set(s string) error {
+	var exchangeRate DatedExchangeRate
+	if err := exchangeRate.UnmarshalText([]byte(s)); err != nil {
+		return err
+	}
+
+	if !exchangeRate.Currency.Valid() {
+		return fmt.Errorf("%w %q", ErrUnknownCurrency, exchangeRate.Currency)
+	}
+
+	*r = exchangeRate
+
+	return nil
+}
+
+// Type implements pflag.Value.
+func (r *DatedExchangeRate) Type() string {
+	return "dated-exchange-rate"
+}
+
+// Scan implements sql.Scanner, decoding a string as UnmarshalText, so that whatever Value stores is read back, and
+// NULL as the zero DatedExchangeRate.
+func (r *DatedExchangeRate) Scan(src interface{}) error {
+	if src == nil {
+		*r = DatedExchangeRate{}
+		return nil
+	}
+
+	b, err := scanText(src)
+	if err != nil {
+		return err
+	}
+
+	return r.UnmarshalText(b)
+}
+
+// Value implements driver.Valuer, encoding the rates as MarshalText.
+func (r DatedExchangeRate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// scanText returns the text of a string or []byte column for Scan, or nil for NULL.
+func scanText(src interface{}) ([]byte, error) {
+	switch v := src.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("cannot scan %T as text", src)
+	}
+}
+
+// encodeRecord encodes the fields as a single CSV record without the line terminator.
+func encodeRecord(record []string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(record); err != nil {
+		return nil, err
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// decodeRecord decodes a single CSV record, which may end with the empty field of the records of the bank.
+func decodeRecord(b []byte) ([]string, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.FieldsPerRecord = -1
+
+	record, err := r.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty record")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Read(); err != io.EOF {
+		return nil, errors.New("more than one record")
+	}
+
+	return record, nil
+}
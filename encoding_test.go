@@ -0,0 +1,258 @@
+package twfxr_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.TextMarshaler   = twfxr.CurrencyUSD
+	_ encoding.TextUnmarshaler = (*twfxr.Currency)(nil)
+	_ flag.Value               = (*twfxr.Currency)(nil)
+	_ sql.Scanner              = (*twfxr.Currency)(nil)
+	_ driver.Valuer            = twfxr.CurrencyUSD
+
+	_ encoding.TextMarshaler   = twfxr.CurrencyExchangeRate{}
+	_ encoding.TextUnmarshaler = (*twfxr.CurrencyExchangeRate)(nil)
+	_ flag.Value               = (*twfxr.CurrencyExchangeRate)(nil)
+	_ sql.Scanner              = (*twfxr.CurrencyExchangeRate)(nil)
+	_ driver.Valuer            = twfxr.CurrencyExchangeRate{}
+
+	_ encoding.TextMarshaler   = twfxr.DatedExchangeRate{}
+	_ encoding.TextUnmarshaler = (*twfxr.DatedExchangeRate)(nil)
+	_ flag.Value               = (*twfxr.DatedExchangeRate)(nil)
+	_ sql.Scanner              = (*twfxr.DatedExchangeRate)(nil)
+	_ driver.Valuer            = twfxr.DatedExchangeRate{}
+)
+
+const usdRecord = "USD," +
+	"本行買入,27.52000,27.84500,27.86500,27.86000,27.85500,27.85000,27.84500,27.84000,27.83500," +
+	"本行賣出,28.19000,27.99500,27.97100,27.97200,27.97300,27.97400,27.97500,27.97600,27.97700"
+
+func usdExchangeRate() twfxr.CurrencyExchangeRate {
+	return twfxr.CurrencyExchangeRate{
+		Currency:              twfxr.CurrencyUSD,
+		BuyingCash:            27.52,
+		BuyingSpot:            27.845,
+		BuyingForward10Days:   27.865,
+		BuyingForward30Days:   27.86,
+		BuyingForward60Days:   27.855,
+		BuyingForward90Days:   27.85,
+		BuyingForward120Days:  27.845,
+		BuyingForward150Days:  27.84,
+		BuyingForward180Days:  27.835,
+		SellingCash:           28.19,
+		SellingSpot:           27.995,
+		SellingForward10Days:  27.971,
+		SellingForward30Days:  27.972,
+		SellingForward60Days:  27.973,
+		SellingForward90Days:  27.974,
+		SellingForward120Days: 27.975,
+		SellingForward150Days: 27.976,
+		SellingForward180Days: 27.977,
+	}
+}
+
+func TestCurrencyText(t *testing.T) {
+	var config struct {
+		Base  twfxr.Currency
+		Watch map[twfxr.Currency]float64
+	}
+
+	err := json.Unmarshal([]byte(`{"Base":"美金","Watch":{"jpy":0.25}}`), &config)
+	assert.NoError(t, err)
+	assert.Equal(t, twfxr.CurrencyUSD, config.Base)
+	assert.Equal(t, map[twfxr.Currency]float64{twfxr.CurrencyJPY: 0.25}, config.Watch)
+
+	b, err := json.Marshal(config)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Base":"USD","Watch":{"JPY":0.25}}`, string(b))
+
+	// A currency newly listed by the bank is decoded as the parser of the rate files does.
+	err = json.Unmarshal([]byte(`{"Base":"XYZ"}`), &config)
+	assert.NoError(t, err)
+	assert.Equal(t, twfxr.Currency("XYZ"), config.Base)
+
+	err = json.Unmarshal([]byte(`{"Base":"xyz"}`), &config)
+	assert.ErrorIs(t, err, twfxr.ErrUnknownCurrency)
+
+	var currency twfxr.Currency
+	assert.NoError(t, currency.UnmarshalText(nil))
+	assert.Equal(t, twfxr.Currency(""), currency)
+}
+
+func TestCurrencyFlag(t *testing.T) {
+	currency := twfxr.CurrencyUSD
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&currency, "currency", "currency")
+
+	assert.NoError(t, fs.Parse([]string{"--currency", "eur"}))
+	assert.Equal(t, twfxr.CurrencyEUR, currency)
+	assert.Equal(t, "EUR", currency.String())
+	assert.Equal(t, "currency", currency.Type())
+
+	assert.Error(t, currency.Set("XYZ"))
+	assert.Equal(t, twfxr.CurrencyEUR, currency)
+}
+
+func TestCurrencySQL(t *testing.T) {
+	value, err := twfxr.CurrencyKRW.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "KRW", value)
+
+	value, err = twfxr.Currency("").Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	var currency twfxr.Currency
+	assert.NoError(t, currency.Scan([]byte("KRW")))
+	assert.Equal(t, twfxr.CurrencyKRW, currency)
+
+	assert.NoError(t, currency.Scan(nil))
+	assert.Equal(t, twfxr.Currency(""), currency)
+
+	// A currency stored by Value is read back even if it is not in the registry.
+	value, err = twfxr.Currency("XAU").Value()
+	assert.NoError(t, err)
+	assert.NoError(t, currency.Scan(value))
+	assert.Equal(t, twfxr.Currency("XAU"), currency)
+
+	assert.ErrorIs(t, currency.Scan("US"), twfxr.ErrUnknownCurrency)
+	assert.Error(t, currency.Scan(42))
+}
+
+func TestCurrencyExchangeRateText(t *testing.T) {
+	b, err := usdExchangeRate().MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, usdRecord, string(b))
+	assert.Equal(t, usdRecord, usdExchangeRate().String())
+
+	testCases := map[string]struct {
+		input string
+		err   bool
+	}{
+		"record":                 {input: usdRecord},
+		"record of the bank":     {input: usdRecord + ",\r\n"},
+		"empty":                  {input: "", err: true},
+		"invalid rate":           {input: usdRecord[:len(usdRecord)-8] + "27.9x700", err: true},
+		"missing field":          {input: "USD,本行買入,27.52000", err: true},
+		"more than one record":   {input: usdRecord + "\n" + usdRecord, err: true},
+		"selling before buying":  {input: "USD,本行賣出" + usdRecord[len("USD,本行買入"):], err: true},
+		"lower case currency":    {input: "usd" + usdRecord[3:], err: true},
+		"surrounding whitespace": {input: " USD , 本行買入" + usdRecord[len("USD,本行買入"):]},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var exchangeRate twfxr.CurrencyExchangeRate
+			err := exchangeRate.UnmarshalText([]byte(tc.input))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, usdExchangeRate(), exchangeRate)
+		})
+	}
+}
+
+func TestCurrencyExchangeRateSQL(t *testing.T) {
+	value, err := usdExchangeRate().Value()
+	assert.NoError(t, err)
+	assert.Equal(t, usdRecord, value)
+
+	var exchangeRate twfxr.CurrencyExchangeRate
+	assert.NoError(t, exchangeRate.Scan([]byte(usdRecord)))
+	assert.Equal(t, usdExchangeRate(), exchangeRate)
+
+	assert.NoError(t, exchangeRate.Scan(nil))
+	assert.Equal(t, twfxr.CurrencyExchangeRate{}, exchangeRate)
+
+	assert.NoError(t, exchangeRate.Set(usdRecord))
+	assert.Equal(t, usdExchangeRate(), exchangeRate)
+	assert.Equal(t, "exchange-rate", exchangeRate.Type())
+
+	assert.ErrorIs(t, exchangeRate.Set("XYZ"+usdRecord[3:]), twfxr.ErrUnknownCurrency)
+	assert.Equal(t, usdExchangeRate(), exchangeRate)
+
+	// A currency stored by Value is read back even if it is not in the registry.
+	xau := usdExchangeRate()
+	xau.Currency = "XAU"
+
+	value, err = xau.Value()
+	assert.NoError(t, err)
+	assert.NoError(t, exchangeRate.Scan(value))
+	assert.Equal(t, xau, exchangeRate)
+}
+
+func TestCurrencyExchangeRateNewCurrency(t *testing.T) {
+	rate := usdExchangeRate()
+	rate.Currency = "XYZ"
+
+	var decoded twfxr.CurrencyExchangeRate
+	assert.NoError(t, decoded.UnmarshalText([]byte("XYZ"+usdRecord[3:])))
+	assert.Equal(t, rate, decoded)
+
+	b, err := json.Marshal(rate)
+	assert.NoError(t, err)
+
+	decoded = twfxr.CurrencyExchangeRate{}
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, rate, decoded)
+}
+
+func TestCurrencyExchangeRateJSONIsAnObject(t *testing.T) {
+	b, err := json.Marshal(usdExchangeRate())
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"Currency":"USD"`)
+	assert.Contains(t, string(b), `"Selling-Spot":27.995`)
+
+	err = json.Unmarshal([]byte(`{"Currency":"US"}`), new(twfxr.CurrencyExchangeRate))
+	assert.ErrorIs(t, err, twfxr.ErrUnknownCurrency)
+}
+
+func TestDatedExchangeRateEncoding(t *testing.T) {
+	rate := twfxr.DatedExchangeRate{
+		Date:                 time.Date(2021, 8, 27, 0, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60)),
+		CurrencyExchangeRate: usdExchangeRate(),
+	}
+
+	b, err := rate.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "20210827,"+usdRecord, string(b))
+
+	var decoded twfxr.DatedExchangeRate
+	assert.NoError(t, decoded.UnmarshalText(b))
+	assert.True(t, rate.Date.Equal(decoded.Date))
+	assert.Equal(t, rate.CurrencyExchangeRate, decoded.CurrencyExchangeRate)
+
+	assert.Error(t, decoded.UnmarshalText([]byte("2021-08-27,"+usdRecord)))
+	assert.Error(t, decoded.UnmarshalText([]byte(usdRecord)))
+
+	// The date is kept in JSON and the fields of the rates are inlined.
+	b, err = json.Marshal(rate)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"Date":"2021-08-27T00:00:00+08:00"`)
+	assert.Contains(t, string(b), `"Currency":"USD"`)
+
+	decoded = twfxr.DatedExchangeRate{}
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.True(t, rate.Date.Equal(decoded.Date))
+	assert.Equal(t, rate.CurrencyExchangeRate, decoded.CurrencyExchangeRate)
+
+	value, err := rate.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "20210827,"+usdRecord, value)
+
+	assert.NoError(t, decoded.Scan(nil))
+	assert.Equal(t, twfxr.DatedExchangeRate{}, decoded)
+}
@@ -0,0 +1,20 @@
+package twfxr
+
+import (
+	"context"
+	"time"
+)
+
+// WithClock sets the clock of the client in the tests.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// WithSleep sets how the client waits before retrying a failed download in the tests.
+func WithSleep(sleep func(ctx context.Context, d time.Duration) error) Option {
+	return func(c *Client) {
+		c.sleep = sleep
+	}
+}
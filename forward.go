@@ -0,0 +1,133 @@
+package twfxr
+
+import (
+	"time"
+)
+
+// Tenor is the term of a forward rate in days.
+type Tenor int
+
+const (
+	Tenor10Days  Tenor = 10  // 遠期10天
+	Tenor30Days  Tenor = 30  // 遠期30天
+	Tenor60Days  Tenor = 60  // 遠期60天
+	Tenor90Days  Tenor = 90  // 遠期90天
+	Tenor120Days Tenor = 120 // 遠期120天
+	Tenor150Days Tenor = 150 // 遠期150天
+	Tenor180Days Tenor = 180 // 遠期180天
+)
+
+// Tenors returns the tenors quoted by the bank in ascending order.
+func Tenors() []Tenor {
+	return []Tenor{Tenor10Days, Tenor30Days, Tenor60Days, Tenor90Days, Tenor120Days, Tenor150Days, Tenor180Days}
+}
+
+// ForwardCurve is the spot rate and the forward rates of one side of a currency keyed by tenor.
+type ForwardCurve struct {
+	Spot     Rate
+	Forwards map[Tenor]Rate
+}
+
+// ForwardCurve returns the forward curve of the given side.
+func (r CurrencyExchangeRate) ForwardCurve(side Side) ForwardCurve {
+	switch side {
+	case SideBuying:
+		return ForwardCurve{
+			Spot: r.BuyingSpot,
+			Forwards: map[Tenor]Rate{
+				Tenor10Days:  r.BuyingForward10Days,
+				Tenor30Days:  r.BuyingForward30Days,
+				Tenor60Days:  r.BuyingForward60Days,
+				Tenor90Days:  r.BuyingForward90Days,
+				Tenor120Days: r.BuyingForward120Days,
+				Tenor150Days: r.BuyingForward150Days,
+				Tenor180Days: r.BuyingForward180Days,
+			},
+		}
+
+	case SideSelling:
+		return ForwardCurve{
+			Spot: r.SellingSpot,
+			Forwards: map[Tenor]Rate{
+				Tenor10Days:  r.SellingForward10Days,
+				Tenor30Days:  r.SellingForward30Days,
+				Tenor60Days:  r.SellingForward60Days,
+				Tenor90Days:  r.SellingForward90Days,
+				Tenor120Days: r.SellingForward120Days,
+				Tenor150Days: r.SellingForward150Days,
+				Tenor180Days: r.SellingForward180Days,
+			},
+		}
+
+	default:
+		return ForwardCurve{}
+	}
+}
+
+// Rate returns the forward rate of the given tenor and whether it is offered by the bank.
+func (c ForwardCurve) Rate(tenor Tenor) (float64, bool) {
+	return c.Forwards[tenor].Float64()
+}
+
+// ForwardPoints returns the forward rate of the given tenor minus the spot rate, and whether both rates are offered by
+// the bank.
+func (c ForwardCurve) ForwardPoints(tenor Tenor) (float64, bool) {
+	spot, ok := c.Spot.Float64()
+	if !ok {
+		return 0, false
+	}
+
+	forward, ok := c.Rate(tenor)
+	if !ok {
+		return 0, false
+	}
+
+	return forward - spot, true
+}
+
+// Interpolate returns the forward rate of an arbitrary number of days by linearly interpolating the two nearest quoted
+// tenors, where the spot rate is taken as the rate of 0 days. It reports false when the number of days is out of the
+// quoted range or either of the nearest rates is not offered by the bank.
+func (c ForwardCurve) Interpolate(days int) (float64, bool) {
+	if days < 0 {
+		return 0, false
+	}
+
+	lowerDays, lower := 0, c.Spot
+
+	for _, tenor := range Tenors() {
+		upperDays, upper := int(tenor), c.Forwards[tenor]
+
+		if days > upperDays {
+			lowerDays, lower = upperDays, upper
+			continue
+		}
+
+		if days == upperDays {
+			return upper.Float64()
+		}
+
+		if days == lowerDays {
+			return lower.Float64()
+		}
+
+		if !lower.Valid() || !upper.Valid() {
+			return 0, false
+		}
+
+		weight := float64(days-lowerDays) / float64(upperDays-lowerDays)
+
+		return float64(lower) + (float64(upper)-float64(lower))*weight, true
+	}
+
+	return 0, false
+}
+
+// InterpolateDate is like Interpolate with the number of calendar days from the quote date to the settlement date in
+// Taiwan.
+func (c ForwardCurve) InterpolateDate(quotedAt, settlement time.Time) (float64, bool) {
+	from := truncateToDate(quotedAt.In(asiaTaipei))
+	to := truncateToDate(settlement.In(asiaTaipei))
+
+	return c.Interpolate(int(to.Sub(from).Hours() / 24))
+}
@@ -0,0 +1,91 @@
+package twfxr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardCurve(t *testing.T) {
+	exchangeRate := twfxr.CurrencyExchangeRate{
+		Currency:              "USD",
+		BuyingSpot:            27.84500,
+		BuyingForward10Days:   27.86500,
+		BuyingForward30Days:   27.86500,
+		BuyingForward60Days:   27.86000,
+		BuyingForward90Days:   27.85500,
+		BuyingForward120Days:  27.85000,
+		BuyingForward150Days:  27.84100,
+		BuyingForward180Days:  27.83400,
+		SellingSpot:           27.99500,
+		SellingForward10Days:  27.97100,
+		SellingForward30Days:  27.97100,
+		SellingForward60Days:  27.97100,
+		SellingForward90Days:  27.97000,
+		SellingForward120Days: 27.97000,
+		SellingForward150Days: 27.96900,
+		SellingForward180Days: 27.96700,
+	}
+
+	curve := exchangeRate.ForwardCurve(twfxr.SideBuying)
+
+	rate, ok := curve.Rate(twfxr.Tenor60Days)
+	assert.True(t, ok)
+	assert.Equal(t, 27.86, rate)
+
+	points, ok := curve.ForwardPoints(twfxr.Tenor10Days)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.02, points, 1e-9)
+
+	points, ok = exchangeRate.ForwardCurve(twfxr.SideSelling).ForwardPoints(twfxr.Tenor180Days)
+	assert.True(t, ok)
+	assert.InDelta(t, -0.028, points, 1e-9)
+
+	testCases := map[string]struct {
+		days int
+		want float64
+		ok   bool
+	}{
+		"spot":                  {days: 0, want: 27.845, ok: true},
+		"between spot and 10D":  {days: 5, want: 27.855, ok: true},
+		"at a tenor":            {days: 90, want: 27.855, ok: true},
+		"between two tenors":    {days: 45, want: 27.8625, ok: true},
+		"beyond the last tenor": {days: 181},
+		"negative days":         {days: -1},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rate, ok := curve.Interpolate(tc.days)
+			assert.Equal(t, tc.ok, ok)
+			assert.InDelta(t, tc.want, rate, 1e-9)
+		})
+	}
+
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+	rate, ok = curve.InterpolateDate(
+		time.Date(2021, 8, 29, 5, 26, 0, 0, taipei),
+		time.Date(2021, 10, 13, 0, 0, 0, 0, taipei),
+	)
+	assert.True(t, ok)
+	assert.InDelta(t, 27.8625, rate, 1e-9)
+}
+
+func TestForwardCurveNotOffered(t *testing.T) {
+	curve := twfxr.CurrencyExchangeRate{Currency: "THB", BuyingSpot: 0.83970}.ForwardCurve(twfxr.SideBuying)
+
+	_, ok := curve.Rate(twfxr.Tenor30Days)
+	assert.False(t, ok)
+
+	_, ok = curve.ForwardPoints(twfxr.Tenor30Days)
+	assert.False(t, ok)
+
+	_, ok = curve.Interpolate(5)
+	assert.False(t, ok)
+
+	rate, ok := curve.Interpolate(0)
+	assert.True(t, ok)
+	assert.Equal(t, 0.8397, rate)
+}
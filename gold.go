@@ -0,0 +1,265 @@
+package twfxr
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// goldCSVPathPrefix is followed by the language of the price file, 0 for Chinese and 1 for English, and the file, e.g.
+// /gold/csv/0/day.
+const goldCSVPathPrefix = "/gold/csv/"
+
+// Headers of the gold passbook price files.
+const (
+	goldHeaderUnit    = "單位"
+	goldHeaderBuying  = "本行買進"
+	goldHeaderSelling = "本行賣出"
+)
+
+// goldEnglishHeaders are the headers of the gold passbook price files in English keyed by the ones in Chinese.
+var goldEnglishHeaders = map[string]string{
+	headerCurrency:    "Currency",
+	goldHeaderUnit:    "Unit",
+	goldHeaderBuying:  "Buying",
+	goldHeaderSelling: "Selling",
+}
+
+// goldChineseHeaders are the headers of the gold passbook price files in Chinese keyed by the ones in English.
+var goldChineseHeaders = invertHeaders(goldEnglishHeaders)
+
+// goldUnits are the units of the prices in the gold passbook price files, which is one gram, in Chinese and in English.
+var goldUnits = map[string]bool{"1公克": true, "1 Gram": true}
+
+// goldFilenamePattern is the filename of a gold passbook price file, which carries the quote time in the format of
+// 200601021504.
+var goldFilenamePattern = regexp.MustCompile(`^GoldPassbook@(\d{12})\.csv$`)
+
+// GoldQuote is the gold passbook (黃金存摺) prices per gram quoted by the bank in a currency.
+type GoldQuote struct {
+	Currency Currency
+	// Buying is the price the bank buys gold at, i.e. what the customer gets for selling a gram. 本行買進
+	Buying Decimal
+	// Selling is the price the bank sells gold at, i.e. what the customer pays for buying a gram. 本行賣出
+	Selling Decimal
+}
+
+// GoldQuotes is the gold passbook prices of the currencies in a price file along with its metadata.
+type GoldQuotes struct {
+	Metadata Metadata
+	// Quotes are the prices in the order published by the bank.
+	Quotes []GoldQuote
+}
+
+// Get returns the prices in the given currency and whether the bank quotes gold in it.
+func (q GoldQuotes) Get(currency Currency) (GoldQuote, bool) {
+	for _, quote := range q.Quotes {
+		if quote.Currency == currency {
+			return quote, true
+		}
+	}
+	return GoldQuote{}, false
+}
+
+// GetGoldQuotes returns the latest gold passbook prices per gram, which are quoted in TWD and USD.
+func (c *Client) GetGoldQuotes(ctx context.Context) (GoldQuotes, error) {
+	path := goldCSVPathPrefix + c.languageIndex() + "/" + dayCSVFile
+
+	file, notModified, err := c.getExchangeRateCSVFile(ctx, path, false)
+	if err != nil {
+		return GoldQuotes{}, err
+	}
+
+	quotes, err := ParseGold(bytes.NewReader(file.Data), file.Filename)
+	if err != nil {
+		return quotes, err
+	}
+
+	stampMetadata(&quotes.Metadata, c.baseURL+path, file, notModified)
+
+	return quotes, nil
+}
+
+// GetGoldQuote returns the latest gold passbook prices per gram in the given currency. An error wrapping ErrNotFound
+// is returned when the bank does not quote gold in the currency.
+func (c *Client) GetGoldQuote(ctx context.Context, currency Currency) (GoldQuote, Metadata, error) {
+	quotes, err := c.GetGoldQuotes(ctx)
+	if err != nil {
+		return GoldQuote{}, quotes.Metadata, err
+	}
+
+	quote, ok := quotes.Get(currency)
+	if !ok {
+		return GoldQuote{}, quotes.Metadata, fmt.Errorf("no gold quote in %s: %w", currency, ErrNotFound)
+	}
+
+	return quote, quotes.Metadata, nil
+}
+
+// GetGoldQuotes is a wrapper of Client.GetGoldQuotes using the default client.
+func GetGoldQuotes(ctx context.Context) (GoldQuotes, error) {
+	return defaultClient.GetGoldQuotes(ctx)
+}
+
+// GetGoldQuote is a wrapper of Client.GetGoldQuote using the default client.
+func GetGoldQuote(ctx context.Context, currency Currency) (GoldQuote, Metadata, error) {
+	return defaultClient.GetGoldQuote(ctx, currency)
+}
+
+// ParseGold parses a gold passbook price file downloaded from the bank, where filename is the original filename of the
+// file, e.g. GoldPassbook@202108271330.csv, which carries the quote time.
+func ParseGold(r io.Reader, filename string) (GoldQuotes, error) {
+	metadata, err := parseFilename(filename, goldFilenamePattern, "GoldPassbook@YYYYMMDDhhmm.csv")
+	if err != nil {
+		return GoldQuotes{Metadata: metadata}, err
+	}
+
+	hash := sha256.New()
+
+	quotes, language, err := parseGoldCSV(io.TeeReader(r, hash))
+	if err != nil {
+		return GoldQuotes{Metadata: metadata}, err
+	}
+
+	metadata.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	metadata.Language = language
+
+	return GoldQuotes{Metadata: metadata, Quotes: quotes}, nil
+}
+
+// ParseGoldFile parses a gold passbook price file previously downloaded from the bank. The file must keep its original
+// name, e.g. GoldPassbook@202108271330.csv.
+func ParseGoldFile(path string) (GoldQuotes, error) {
+	var quotes GoldQuotes
+
+	err := parseFile(path, func(r io.Reader, filename string) (err error) {
+		quotes, err = ParseGold(r, filename)
+		return err
+	})
+
+	return quotes, err
+}
+
+// parseGoldCSV parses the gold passbook price file, which has the columns 幣別, 單位, 本行買進 and 本行賣出 in any order,
+// and returns the prices in the order of the file along with the language of the header.
+func parseGoldCSV(reader io.Reader) ([]GoldQuote, string, error) {
+	var (
+		columns  map[string]int
+		language string
+		quotes   []GoldQuote
+	)
+
+	seen := make(map[Currency]bool)
+
+	err := readRecords(reader, func(line int, record []string) error {
+		if columns == nil {
+			var err error
+			columns, language, err = goldColumns(record)
+			return err
+		}
+
+		quote, err := parseGoldRecord(line, record, columns)
+		if err != nil {
+			return err
+		}
+
+		if seen[quote.Currency] {
+			return &ParseError{Line: line, Err: fmt.Errorf("duplicate currency %s", quote.Currency)}
+		}
+		seen[quote.Currency] = true
+
+		quotes = append(quotes, quote)
+
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return quotes, language, nil
+}
+
+// goldColumns resolves the positions of the columns of the gold passbook price file from the header, which may be in
+// English and may start with a UTF-8 BOM.
+func goldColumns(header []string) (map[string]int, string, error) {
+	columns := make(map[string]int)
+	language := LanguageZhTW
+
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.TrimSpace(name)
+
+		if zh, ok := goldChineseHeaders[name]; ok {
+			name = zh
+			language = LanguageEn
+		}
+
+		if _, ok := goldEnglishHeaders[name]; !ok {
+			continue
+		}
+
+		if _, ok := columns[name]; ok {
+			return nil, "", &ParseError{Line: 1, Column: i + 1, Err: fmt.Errorf("duplicate column %s", name)}
+		}
+		columns[name] = i
+	}
+
+	for _, name := range []string{headerCurrency, goldHeaderUnit, goldHeaderBuying, goldHeaderSelling} {
+		if _, ok := columns[name]; !ok {
+			return nil, "", &ParseError{Line: 1, Err: fmt.Errorf("missing column %s", name)}
+		}
+	}
+
+	return columns, language, nil
+}
+
+// parseGoldRecord maps a record into GoldQuote, validating every field.
+func parseGoldRecord(line int, record []string, columns map[string]int) (GoldQuote, error) {
+	currency, err := field(line, record, columns[headerCurrency])
+	if err != nil {
+		return GoldQuote{}, err
+	}
+
+	if !currencyPattern.MatchString(currency) {
+		return GoldQuote{}, &ParseError{Line: line, Column: columns[headerCurrency] + 1, Err: fmt.Errorf("invalid currency %q", currency)}
+	}
+
+	unit, err := field(line, record, columns[goldHeaderUnit])
+	if err != nil {
+		return GoldQuote{}, err
+	}
+
+	if !goldUnits[unit] {
+		return GoldQuote{}, &ParseError{Line: line, Column: columns[goldHeaderUnit] + 1, Err: fmt.Errorf("unsupported unit %q", unit)}
+	}
+
+	quote := GoldQuote{Currency: Currency(currency)}
+
+	for name, price := range map[string]*Decimal{goldHeaderBuying: &quote.Buying, goldHeaderSelling: &quote.Selling} {
+		s, err := field(line, record, columns[name])
+		if err != nil {
+			return GoldQuote{}, err
+		}
+
+		// Prices are checked like the board rates before parsing, as ParseDecimal also accepts e.g. +1605 and .5.
+		if !ratePattern.MatchString(s) {
+			return GoldQuote{}, &ParseError{Line: line, Column: columns[name] + 1, Err: fmt.Errorf("invalid price %q", s)}
+		}
+
+		d, err := ParseDecimal(s)
+		if err != nil || d.Sign() <= 0 {
+			return GoldQuote{}, &ParseError{Line: line, Column: columns[name] + 1, Err: fmt.Errorf("invalid price %q", s)}
+		}
+
+		*price = d
+	}
+
+	return quote, nil
+}
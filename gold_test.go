@@ -0,0 +1,160 @@
+package twfxr_test
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+//go:embed testdata/GoldPassbook@202108271330.csv
+var GoldPassbookPage string
+
+//go:embed testdata/en/GoldPassbook@202108271330.csv
+var GoldPassbookPageEn string
+
+type goldSuite struct {
+	suite.Suite
+
+	transport *httpmock.MockTransport
+}
+
+func (suite *goldSuite) SetupTest() {
+	suite.transport = httpmock.NewMockTransport()
+
+	for path, page := range map[string]string{
+		"https://rate.bot.com.tw/gold/csv/0/day": GoldPassbookPage,
+		"https://rate.bot.com.tw/gold/csv/1/day": GoldPassbookPageEn,
+	} {
+		page := page
+		suite.transport.RegisterResponder(http.MethodGet, path,
+			func(req *http.Request) (*http.Response, error) {
+				resp := newResponse(http.StatusOK, page)
+				resp.Header.Add("Content-Disposition", `attachment; filename="GoldPassbook@202108271330.csv"`)
+				return resp, nil
+			},
+		)
+	}
+}
+
+func (suite *goldSuite) client(opts ...twfxr.Option) *twfxr.Client {
+	return twfxr.NewClient(append(opts, twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}))...)
+}
+
+func (suite *goldSuite) TestGetGoldQuotes() {
+	quotes, err := suite.client().GetGoldQuotes(context.Background())
+	suite.Require().NoError(err)
+
+	suite.Equal([]twfxr.GoldQuote{
+		{
+			Currency: twfxr.CurrencyTWD,
+			Buying:   twfxr.MustParseDecimal("1605"),
+			Selling:  twfxr.MustParseDecimal("1625"),
+		},
+		{
+			Currency: twfxr.CurrencyUSD,
+			Buying:   twfxr.MustParseDecimal("57.40"),
+			Selling:  twfxr.MustParseDecimal("58.12"),
+		},
+	}, quotes.Quotes)
+
+	metadata := quotes.Metadata
+	suite.Equal(time.Date(2021, 8, 27, 13, 30, 0, 0, time.FixedZone("UTC+8", 8*60*60)), metadata.QuotedAt)
+	suite.Equal("GoldPassbook@202108271330.csv", metadata.Filename)
+	suite.Equal("https://rate.bot.com.tw/gold/csv/0/day", metadata.SourceURL)
+	suite.Equal(twfxr.LanguageZhTW, metadata.Language)
+	suite.NotEmpty(metadata.SHA256)
+	suite.False(metadata.FetchedAt.IsZero())
+}
+
+func (suite *goldSuite) TestGetGoldQuotesInEnglish() {
+	quotes, err := suite.client(twfxr.WithLanguage(twfxr.LanguageEn)).GetGoldQuotes(context.Background())
+	suite.Require().NoError(err)
+
+	expected, err := suite.client().GetGoldQuotes(context.Background())
+	suite.Require().NoError(err)
+
+	suite.Equal(expected.Quotes, quotes.Quotes)
+	suite.Equal("https://rate.bot.com.tw/gold/csv/1/day", quotes.Metadata.SourceURL)
+	suite.Equal(twfxr.LanguageEn, quotes.Metadata.Language)
+}
+
+func (suite *goldSuite) TestGetGoldQuote() {
+	quote, _, err := suite.client().GetGoldQuote(context.Background(), twfxr.CurrencyUSD)
+	suite.NoError(err)
+	suite.Equal("58.12", quote.Selling.String())
+
+	_, _, err = suite.client().GetGoldQuote(context.Background(), twfxr.CurrencyJPY)
+	suite.ErrorIs(err, twfxr.ErrNotFound)
+}
+
+func TestGoldSuite(t *testing.T) {
+	suite.Run(t, new(goldSuite))
+}
+
+func TestParseGoldError(t *testing.T) {
+	const header = "幣別,單位,本行買進,本行賣出\n"
+
+	testCases := map[string]struct {
+		input    string
+		filename string
+		line     int
+		column   int
+	}{
+		"invalid filename":   {input: header, filename: "ExchangeRate@202108271330.csv"},
+		"missing column":     {input: "幣別,單位,本行買進\n", line: 1},
+		"duplicate column":   {input: "幣別,單位,本行買進,本行賣出,本行賣出\n", line: 1, column: 5},
+		"invalid currency":   {input: header + "twd,1公克,1605,1625,\n", line: 2, column: 1},
+		"unsupported unit":   {input: header + "USD,1英兩,1785.20,1807.60,\n", line: 2, column: 2},
+		"invalid price":      {input: header + "TWD,1公克,-,1625,\n", line: 2, column: 3},
+		"signed price":       {input: header + "TWD,1公克,+1605,1625,\n", line: 2, column: 3},
+		"fractional price":   {input: header + "TWD,1公克,1605,.5,\n", line: 2, column: 4},
+		"zero price":         {input: header + "TWD,1公克,1605,0,\n", line: 2, column: 4},
+		"missing field":      {input: header + "TWD,1公克,1605\n", line: 2, column: 4},
+		"duplicate currency": {input: header + "TWD,1公克,1605,1625,\nTWD,1公克,1605,1625,\n", line: 3},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			filename := tc.filename
+			if filename == "" {
+				filename = "GoldPassbook@202108271330.csv"
+			}
+
+			_, err := twfxr.ParseGold(strings.NewReader(tc.input), filename)
+			if !assert.Error(t, err) || tc.line == 0 {
+				return
+			}
+
+			var parseErr *twfxr.ParseError
+			if assert.ErrorAs(t, err, &parseErr) {
+				assert.Equal(t, tc.line, parseErr.Line)
+				assert.Equal(t, tc.column, parseErr.Column)
+			}
+		})
+	}
+}
+
+func TestParseGoldFile(t *testing.T) {
+	quotes, err := twfxr.ParseGoldFile("testdata/GoldPassbook@202108271330.csv")
+	assert.NoError(t, err)
+	assert.Len(t, quotes.Quotes, 2)
+	assert.Empty(t, quotes.Metadata.SourceURL)
+
+	quote, ok := quotes.Get(twfxr.CurrencyTWD)
+	assert.True(t, ok)
+	assert.Equal(t, "1605", quote.Buying.String())
+
+	_, ok = quotes.Get(twfxr.CurrencyJPY)
+	assert.False(t, ok)
+
+	_, err = twfxr.ParseGoldFile("testdata/missing.csv")
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package twfxr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// historyDateLayout is the layout of the 資料日期 column of the history files.
+const historyDateLayout = "20060102"
+
+// DatedExchangeRate is the exchange rate of a currency on a specific date.
+type DatedExchangeRate struct {
+	Date time.Time
+	CurrencyExchangeRate
+}
+
+// GetCurrencyHistory returns the daily board rates of the given currency between from and to (both inclusive) in
+// chronological order. The calendar dates of from and to in their own locations are used.
+func (c *Client) GetCurrencyHistory(ctx context.Context, currency Currency, from, to time.Time) ([]DatedExchangeRate, error) {
+	from, to = truncateToDate(from), truncateToDate(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: %s is before %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+
+	var history []DatedExchangeRate
+
+	now := c.now().In(asiaTaipei)
+
+	// The history files are named by the period and the currency, where the period is either L3M (last 3 months),
+	// L6M (last 6 months) or a year, e.g. L3M/USD.
+	for _, period := range historyPeriods(now, from, to) {
+		// Only the files of the past years never change.
+		historical := period != "L3M" && period != "L6M" && period != strconv.Itoa(now.Year())
+
+		file, _, err := c.getExchangeRateCSVFile(ctx, c.csvPath(period+"/"+string(currency)), historical)
+		if err != nil {
+			return nil, err
+		}
+
+		rates, err := parseHistoryCSV(bytes.NewReader(file.Data))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rate := range rates {
+			if rate.Date.Before(from) || rate.Date.After(to) {
+				continue
+			}
+			history = append(history, rate)
+		}
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Date.Before(history[j].Date)
+	})
+
+	return history, nil
+}
+
+// GetCurrencyHistory is a wrapper of Client.GetCurrencyHistory using the default client.
+func GetCurrencyHistory(ctx context.Context, currency Currency, from, to time.Time) ([]DatedExchangeRate, error) {
+	return defaultClient.GetCurrencyHistory(ctx, currency, from, to)
+}
+
+// historyPeriods returns the smallest set of history files covering the dates between from and to.
+func historyPeriods(now, from, to time.Time) []string {
+	today := truncateToDate(now)
+
+	switch {
+	case !from.Before(today.AddDate(0, -3, 0)):
+		return []string{"L3M"}
+
+	case !from.Before(today.AddDate(0, -6, 0)):
+		return []string{"L6M"}
+	}
+
+	last := to.Year()
+	if last > today.Year() {
+		last = today.Year()
+	}
+
+	var periods []string
+	for year := from.Year(); year <= last; year++ {
+		periods = append(periods, strconv.Itoa(year))
+	}
+
+	return periods
+}
+
+func truncateToDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, asiaTaipei)
+}
+
+// parseHistoryCSV parses the history file of a currency, which has the columns of the daily board rate file and the
+// date.
+func parseHistoryCSV(reader io.Reader) ([]DatedExchangeRate, error) {
+	var rates []DatedExchangeRate
+
+	err := readCSV(reader, func(mapping *columnMapping, line int, record []string) error {
+		rate, err := mapping.parseDated(line, record)
+		if err != nil {
+			return err
+		}
+
+		rates = append(rates, rate)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// parseDated maps a record of the history file into DatedExchangeRate, validating every field.
+func (m *columnMapping) parseDated(line int, record []string) (DatedExchangeRate, error) {
+	s, column, err := m.column(line, record, headerDate)
+	if err != nil {
+		return DatedExchangeRate{}, err
+	}
+
+	date, err := time.ParseInLocation(historyDateLayout, s, asiaTaipei)
+	if err != nil {
+		return DatedExchangeRate{}, &ParseError{Line: line, Column: column, Err: fmt.Errorf("invalid date: %w", err)}
+	}
+
+	exchangeRate, err := m.parse(line, record)
+	if err != nil {
+		return DatedExchangeRate{}, err
+	}
+
+	return DatedExchangeRate{Date: date, CurrencyExchangeRate: exchangeRate}, nil
+}
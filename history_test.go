@@ -0,0 +1,148 @@
+package twfxr_test
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/suite"
+)
+
+//go:embed testdata/ExchangeRateHistory@USD2021.csv
+var ExchangeRateHistoryPage string
+
+type historySuite struct {
+	suite.Suite
+
+	transport *httpmock.MockTransport
+	client    *twfxr.Client
+}
+
+func (suite *historySuite) SetupTest() {
+	suite.transport = httpmock.NewMockTransport()
+	suite.transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/2021/USD",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, ExchangeRateHistoryPage)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+	suite.transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/2020/USD",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, "")
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+
+	suite.client = twfxr.NewClient(twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}))
+}
+
+func (suite *historySuite) TestGetCurrencyHistory() {
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+
+	history, err := suite.client.GetCurrencyHistory(context.Background(), twfxr.CurrencyUSD,
+		time.Date(2021, 8, 24, 0, 0, 0, 0, taipei),
+		time.Date(2021, 8, 26, 0, 0, 0, 0, taipei),
+	)
+	suite.NoError(err)
+
+	suite.Len(history, 3)
+	suite.Equal(time.Date(2021, 8, 24, 0, 0, 0, 0, taipei), history[0].Date)
+	suite.Equal(time.Date(2021, 8, 25, 0, 0, 0, 0, taipei), history[1].Date)
+	suite.Equal(time.Date(2021, 8, 26, 0, 0, 0, 0, taipei), history[2].Date)
+	suite.Equal(twfxr.CurrencyUSD, history[0].Currency)
+	suite.Equal(twfxr.Rate(27.925), history[0].BuyingSpot)
+	suite.Equal(twfxr.Rate(28.055), history[2].SellingSpot)
+	suite.Equal(twfxr.Rate(27.895), history[2].BuyingForward180Days)
+}
+
+func (suite *historySuite) TestGetCurrencyHistoryAcrossYears() {
+	history, err := suite.client.GetCurrencyHistory(context.Background(), twfxr.CurrencyUSD,
+		time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+	suite.NoError(err)
+	suite.Len(history, 1)
+	suite.Equal(2, suite.transport.GetTotalCallCount())
+}
+
+func (suite *historySuite) TestGetCurrencyHistoryInvalidRange() {
+	_, err := suite.client.GetCurrencyHistory(context.Background(), twfxr.CurrencyUSD,
+		time.Date(2021, 8, 26, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 8, 24, 0, 0, 0, 0, time.UTC),
+	)
+	suite.Error(err)
+}
+
+func (suite *historySuite) TestGetCurrencyHistoryPeriods() {
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+	now := time.Date(2021, 8, 29, 12, 0, 0, 0, taipei)
+
+	testCases := map[string]struct {
+		from, to time.Time
+		expected []string
+	}{
+		"last 3 months": {
+			from:     time.Date(2021, 5, 29, 0, 0, 0, 0, taipei),
+			to:       time.Date(2021, 8, 29, 0, 0, 0, 0, taipei),
+			expected: []string{"L3M"},
+		},
+		"last 6 months": {
+			from:     time.Date(2021, 5, 28, 0, 0, 0, 0, taipei),
+			to:       time.Date(2021, 6, 30, 0, 0, 0, 0, taipei),
+			expected: []string{"L6M"},
+		},
+		"current year": {
+			from:     time.Date(2021, 2, 27, 0, 0, 0, 0, taipei),
+			to:       time.Date(2021, 8, 29, 0, 0, 0, 0, taipei),
+			expected: []string{"2021"},
+		},
+		"past years": {
+			from:     time.Date(2019, 6, 1, 0, 0, 0, 0, taipei),
+			to:       time.Date(2020, 2, 1, 0, 0, 0, 0, taipei),
+			expected: []string{"2019", "2020"},
+		},
+		"until the future": {
+			from:     time.Date(2020, 12, 1, 0, 0, 0, 0, taipei),
+			to:       time.Date(2022, 1, 31, 0, 0, 0, 0, taipei),
+			expected: []string{"2020", "2021"},
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			var periods []string
+
+			transport := httpmock.NewMockTransport()
+			transport.RegisterRegexpResponder(http.MethodGet, regexp.MustCompile(`^https://rate\.bot\.com\.tw/xrt/flcsv/0/(\w+)/USD$`),
+				func(req *http.Request) (*http.Response, error) {
+					periods = append(periods, strings.Split(req.URL.Path, "/")[4])
+
+					resp := newResponse(http.StatusOK, "")
+					resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+					return resp, nil
+				},
+			)
+
+			client := twfxr.NewClient(
+				twfxr.WithHTTPClient(&http.Client{Transport: transport}),
+				twfxr.WithClock(func() time.Time { return now }),
+			)
+
+			_, err := client.GetCurrencyHistory(context.Background(), twfxr.CurrencyUSD, tc.from, tc.to)
+			suite.NoError(err)
+			suite.Equal(tc.expected, periods)
+		})
+	}
+}
+
+func TestHistorySuite(t *testing.T) {
+	suite.Run(t, new(historySuite))
+}
@@ -0,0 +1,114 @@
+package twfxr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// intradayCSVSuffix follows the date in the format of 2006-01-02 in the name of the intraday rate file.
+const intradayCSVSuffix = "/all"
+
+// QuoteSnapshot is the board rates quoted at QuotedAt, which stay in effect until the next revision.
+type QuoteSnapshot struct {
+	QuotedAt time.Time
+	Rates    map[Currency]CurrencyExchangeRate
+}
+
+// IntradayQuotes is every revision of the board rates in a business day, in chronological order.
+type IntradayQuotes []QuoteSnapshot
+
+// RateAt returns the snapshot in effect at the given instant, i.e. the last revision quoted at or before t. An error
+// wrapping ErrNotFound is returned when t is before the first revision.
+func (q IntradayQuotes) RateAt(t time.Time) (QuoteSnapshot, error) {
+	i := sort.Search(len(q), func(i int) bool {
+		return q[i].QuotedAt.After(t)
+	})
+
+	if i == 0 {
+		return QuoteSnapshot{}, fmt.Errorf("no quote in effect at %s: %w", t.Format(time.RFC3339), ErrNotFound)
+	}
+
+	return q[i-1], nil
+}
+
+// GetIntradayQuotes returns every revision of the board rates quoted on the given date. The calendar date of the given
+// time in its own location is used. An error wrapping ErrNotFound is returned when there is no quote on that date.
+func (c *Client) GetIntradayQuotes(ctx context.Context, date time.Time) (IntradayQuotes, error) {
+	day := date.Format("2006-01-02")
+
+	file, _, err := c.getExchangeRateCSVFile(ctx, c.csvPath(day+intradayCSVSuffix), c.isPastDate(date))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("no quote on %s: %w", day, err)
+		}
+		return nil, err
+	}
+
+	quotes, err := parseIntradayCSV(bytes.NewReader(file.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no quote on %s: %w", day, ErrNotFound)
+	}
+
+	return quotes, nil
+}
+
+// GetIntradayQuotes is a wrapper of Client.GetIntradayQuotes using the default client.
+func GetIntradayQuotes(ctx context.Context, date time.Time) (IntradayQuotes, error) {
+	return defaultClient.GetIntradayQuotes(ctx, date)
+}
+
+// parseIntradayCSV parses the intraday quote file, which has the columns of the daily board rate file and the quote
+// time.
+func parseIntradayCSV(reader io.Reader) (IntradayQuotes, error) {
+	snapshots := make(map[time.Time]QuoteSnapshot)
+
+	err := readCSV(reader, func(mapping *columnMapping, line int, record []string) error {
+		s, column, err := mapping.column(line, record, headerQuotedTime)
+		if err != nil {
+			return err
+		}
+
+		quotedAt, err := time.ParseInLocation("2006/01/02 15:04:05", s, asiaTaipei)
+		if err != nil {
+			return &ParseError{Line: line, Column: column, Err: fmt.Errorf("invalid quote time: %w", err)}
+		}
+
+		exchangeRate, err := mapping.parse(line, record)
+		if err != nil {
+			return err
+		}
+
+		snapshot, ok := snapshots[quotedAt]
+		if !ok {
+			snapshot = QuoteSnapshot{QuotedAt: quotedAt, Rates: make(map[Currency]CurrencyExchangeRate)}
+			snapshots[quotedAt] = snapshot
+		}
+
+		snapshot.Rates[exchangeRate.Currency] = exchangeRate
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make(IntradayQuotes, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		quotes = append(quotes, snapshot)
+	}
+
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].QuotedAt.Before(quotes[j].QuotedAt)
+	})
+
+	return quotes, nil
+}
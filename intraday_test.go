@@ -0,0 +1,96 @@
+package twfxr_test
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/suite"
+)
+
+//go:embed testdata/ExchangeRateIntraday@20210827.csv
+var ExchangeRateIntradayPage string
+
+type intradaySuite struct {
+	suite.Suite
+
+	client *twfxr.Client
+}
+
+func (suite *intradaySuite) SetupTest() {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/2021-08-27/all",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, ExchangeRateIntradayPage)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108271559.csv"`)
+			return resp, nil
+		},
+	)
+	transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/2021-08-28/all",
+		httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+	suite.client = twfxr.NewClient(twfxr.WithHTTPClient(&http.Client{Transport: transport}))
+}
+
+func (suite *intradaySuite) TestGetIntradayQuotes() {
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+
+	quotes, err := suite.client.GetIntradayQuotes(context.Background(), time.Date(2021, 8, 27, 0, 0, 0, 0, taipei))
+	suite.NoError(err)
+
+	suite.Len(quotes, 3)
+	suite.Equal(time.Date(2021, 8, 27, 9, 0, 12, 0, taipei), quotes[0].QuotedAt)
+	suite.Equal(time.Date(2021, 8, 27, 10, 31, 5, 0, taipei), quotes[1].QuotedAt)
+	suite.Equal(time.Date(2021, 8, 27, 15, 59, 48, 0, taipei), quotes[2].QuotedAt)
+	suite.Len(quotes[0].Rates, 3)
+	suite.Equal(twfxr.Rate(27.845), quotes[0].Rates[twfxr.CurrencyUSD].BuyingSpot)
+
+	testCases := map[string]struct {
+		at       time.Time
+		quotedAt time.Time
+		err      error
+	}{
+		"before the first revision": {
+			at:  time.Date(2021, 8, 27, 8, 59, 0, 0, taipei),
+			err: twfxr.ErrNotFound,
+		},
+		"at a revision": {
+			at:       time.Date(2021, 8, 27, 10, 31, 5, 0, taipei),
+			quotedAt: time.Date(2021, 8, 27, 10, 31, 5, 0, taipei),
+		},
+		"between revisions": {
+			at:       time.Date(2021, 8, 27, 2, 0, 0, 0, time.UTC),
+			quotedAt: time.Date(2021, 8, 27, 9, 0, 12, 0, taipei),
+		},
+		"after the last revision": {
+			at:       time.Date(2021, 8, 27, 18, 0, 0, 0, taipei),
+			quotedAt: time.Date(2021, 8, 27, 15, 59, 48, 0, taipei),
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			snapshot, err := quotes.RateAt(tc.at)
+			if tc.err != nil {
+				suite.ErrorIs(err, tc.err)
+				return
+			}
+
+			suite.NoError(err)
+			suite.Equal(tc.quotedAt, snapshot.QuotedAt)
+		})
+	}
+}
+
+func (suite *intradaySuite) TestGetIntradayQuotesNotFound() {
+	_, err := suite.client.GetIntradayQuotes(context.Background(), time.Date(2021, 8, 28, 0, 0, 0, 0, time.UTC))
+	suite.ErrorIs(err, twfxr.ErrNotFound)
+}
+
+func TestIntradaySuite(t *testing.T) {
+	suite.Run(t, new(intradaySuite))
+}
@@ -0,0 +1,99 @@
+package twfxr
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Languages of the rate files and the names of the currencies.
+const (
+	LanguageZhTW = "zh-TW" // Traditional Chinese
+	LanguageEn   = "en"    // English
+)
+
+// ParseLanguage parses a language of the rate files case-insensitively, which is either LanguageZhTW or LanguageEn.
+// English with a region, e.g. en-US, is taken as LanguageEn.
+func ParseLanguage(s string) (string, error) {
+	lower := strings.ToLower(strings.TrimSpace(s))
+
+	switch {
+	case lower == strings.ToLower(LanguageZhTW):
+		return LanguageZhTW, nil
+	case lower == LanguageEn, strings.HasPrefix(lower, LanguageEn+"-"), strings.HasPrefix(lower, LanguageEn+"_"):
+		return LanguageEn, nil
+	default:
+		return "", fmt.Errorf("unsupported language %s", s)
+	}
+}
+
+// filenamePattern is the filename of a daily board rate file, which carries the quote time in the format of
+// 200601021504.
+var filenamePattern = regexp.MustCompile(`^ExchangeRate@(\d{12})\.csv$`)
+
+// metadataHeaders are the headers of the response kept in Metadata.
+var metadataHeaders = []string{"Cache-Control", "Content-Disposition", "Content-Type", "Date", "ETag", "Last-Modified"}
+
+// Metadata describes a daily board rate file and how it was fetched.
+type Metadata struct {
+	// QuotedAt is when the rates were quoted by the bank.
+	QuotedAt time.Time
+	// NotModified reports whether the bank responded 304 Not Modified to the revalidation of the last download, in
+	// which case the rates are the ones parsed from the last download.
+	NotModified bool
+
+	// SourceURL is the URL the file was downloaded from, or empty for a local file.
+	SourceURL string
+	// Filename is the original filename of the file, e.g. ExchangeRate@202108290526.csv.
+	Filename string
+	// FetchedAt is when the file was downloaded or last revalidated, which is earlier than the call for a cached file.
+	// It is zero for a local file.
+	FetchedAt time.Time
+	// Header is the headers of the response of interest, i.e. Cache-Control, Content-Disposition, Content-Type, Date,
+	// ETag and Last-Modified. It is nil for a local file.
+	Header http.Header
+	// SHA256 is the hex-encoded SHA-256 of the raw CSV file. It is empty for a RateReader, which has not read the file
+	// yet.
+	SHA256 string
+	// Language is the language of the headers of the file, e.g. zh-TW.
+	Language string
+}
+
+// parseMetadata parses the filename of a daily board rate file, e.g. ExchangeRate@202108280526.csv.
+func parseMetadata(filename string) (Metadata, error) {
+	return parseFilename(filename, filenamePattern, "ExchangeRate@YYYYMMDDhhmm.csv")
+}
+
+// parseFilename parses the quote time out of a filename matching the given pattern, whose only group is the quote time
+// in the format of 200601021504. The expected form of the filename is shown in the error.
+func parseFilename(filename string, pattern *regexp.Regexp, expected string) (Metadata, error) {
+	metadata := Metadata{Filename: filename}
+
+	matches := pattern.FindStringSubmatch(filename)
+	if matches == nil {
+		return metadata, fmt.Errorf("failed to parse filename %q: expected %s", filename, expected)
+	}
+
+	quotedAt, err := time.ParseInLocation("200601021504", matches[1], asiaTaipei)
+	if err != nil {
+		return metadata, fmt.Errorf("failed to parse filename %q: %w", filename, err)
+	}
+	metadata.QuotedAt = quotedAt
+
+	return metadata, nil
+}
+
+// filterHeader returns the headers of interest of a response.
+func filterHeader(header http.Header) http.Header {
+	filtered := make(http.Header)
+
+	for _, key := range metadataHeaders {
+		if values := header.Values(key); len(values) > 0 {
+			filtered[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,339 @@
+package twfxr
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Headers of the rate files.
+const (
+	headerCurrency   = "幣別"
+	headerMarker     = "匯率"
+	headerDate       = "資料日期"
+	headerQuotedTime = "掛牌時間"
+
+	markerBuying  = "本行買入"
+	markerSelling = "本行賣出"
+)
+
+// rateHeaders are the headers of a block of rates, in the order of the fields returned by CurrencyExchangeRate.rates.
+var rateHeaders = []string{"現金", "即期", "遠期10天", "遠期30天", "遠期60天", "遠期90天", "遠期120天", "遠期150天", "遠期180天"}
+
+// englishHeaders are the headers and the markers of the rate files in English keyed by the ones in Chinese.
+var englishHeaders = map[string]string{
+	headerCurrency:   "Currency",
+	headerMarker:     "Rate",
+	headerDate:       "Data Date",
+	headerQuotedTime: "Quoted Time",
+	markerBuying:     "Buying",
+	markerSelling:    "Selling",
+	"現金":             "Cash",
+	"即期":             "Spot",
+	"遠期10天":          "Forward-10Days",
+	"遠期30天":          "Forward-30Days",
+	"遠期60天":          "Forward-60Days",
+	"遠期90天":          "Forward-90Days",
+	"遠期120天":         "Forward-120Days",
+	"遠期150天":         "Forward-150Days",
+	"遠期180天":         "Forward-180Days",
+}
+
+// chineseHeaders are the headers and the markers of the rate files in Chinese keyed by the ones in English.
+var chineseHeaders = invertHeaders(englishHeaders)
+
+// invertHeaders returns the headers in Chinese keyed by the ones in English from the headers in English keyed by the
+// ones in Chinese.
+func invertHeaders(englishHeaders map[string]string) map[string]string {
+	m := make(map[string]string, len(englishHeaders))
+	for zh, en := range englishHeaders {
+		m[en] = zh
+	}
+	return m
+}
+
+// localizeHeader translates a header or a marker in Chinese into the given language.
+func localizeHeader(name, lang string) string {
+	if lang == LanguageEn {
+		return englishHeaders[name]
+	}
+	return name
+}
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ratePattern is a rate in the rate files, which is a plain decimal literal, e.g. 27.84500. Signs, exponents,
+// hexadecimal floats, NaN and Inf are rejected.
+var ratePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// ParseError is returned when a rate file is malformed.
+type ParseError struct {
+	// Line is the line of the malformed record, where the header is line 1. As the bank's files have neither blank
+	// lines nor multi-line fields, it is also the line in the file.
+	Line int
+	// Column is the 1-based column of the malformed field, or 0 when the whole record is malformed.
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// rateBlock is a block of rates of the same side, which starts with the 匯率 column whose values are either 本行買入 or
+// 本行賣出.
+type rateBlock struct {
+	marker  int
+	columns []int // in the order of rateHeaders
+}
+
+// columnMapping is the positions of the columns of a rate file resolved from its header.
+type columnMapping struct {
+	// columns are the positions of the columns out of the blocks, e.g. 幣別.
+	columns map[string]int
+	blocks  []rateBlock
+	// language is the language of the header.
+	language string
+}
+
+// newColumnMapping resolves the positions of the columns from the header, which has a column of the currency and two
+// blocks of rates, one for each side. Each block starts with a 匯率 column followed by the columns of rates in any
+// order. The header may start with a UTF-8 BOM, and may be in English, e.g. Currency and Rate, in which case the
+// columns are still named in Chinese.
+func newColumnMapping(header []string) (*columnMapping, error) {
+	m := &columnMapping{columns: make(map[string]int), language: LanguageZhTW}
+
+	var block map[string]int
+
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.TrimSpace(name)
+
+		if zh, ok := chineseHeaders[name]; ok {
+			name = zh
+			m.language = LanguageEn
+		}
+
+		switch {
+		case name == headerMarker:
+			if err := m.addBlock(block); err != nil {
+				return nil, err
+			}
+			block = map[string]int{headerMarker: i}
+
+		case block != nil && isRateHeader(name):
+			if _, ok := block[name]; ok {
+				return nil, &ParseError{Line: 1, Column: i + 1, Err: fmt.Errorf("duplicate column %s", name)}
+			}
+			block[name] = i
+
+		case name != "":
+			m.columns[name] = i
+		}
+	}
+
+	if err := m.addBlock(block); err != nil {
+		return nil, err
+	}
+
+	if _, ok := m.columns[headerCurrency]; !ok {
+		return nil, &ParseError{Line: 1, Err: fmt.Errorf("missing column %s", headerCurrency)}
+	}
+
+	if len(m.blocks) != 2 {
+		return nil, &ParseError{Line: 1, Err: fmt.Errorf("expected 2 blocks of rates but got %d", len(m.blocks))}
+	}
+
+	return m, nil
+}
+
+// mustColumnMapping is like newColumnMapping but panics if the header is malformed. It is used for the headers built
+// by the package.
+func mustColumnMapping(header []string) *columnMapping {
+	m, err := newColumnMapping(header)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func isRateHeader(name string) bool {
+	for _, h := range rateHeaders {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *columnMapping) addBlock(block map[string]int) error {
+	if block == nil {
+		return nil
+	}
+
+	b := rateBlock{marker: block[headerMarker]}
+	for _, h := range rateHeaders {
+		i, ok := block[h]
+		if !ok {
+			return &ParseError{Line: 1, Err: fmt.Errorf("missing column %s after column %d", h, b.marker+1)}
+		}
+		b.columns = append(b.columns, i)
+	}
+
+	m.blocks = append(m.blocks, b)
+
+	return nil
+}
+
+// field returns the field of the given column of the record.
+func field(line int, record []string, column int) (string, error) {
+	if column >= len(record) {
+		return "", &ParseError{Line: line, Column: column + 1, Err: errors.New("missing field")}
+	}
+	return strings.TrimSpace(record[column]), nil
+}
+
+// column returns the field of a column out of the blocks, e.g. 資料日期.
+func (m *columnMapping) column(line int, record []string, name string) (string, int, error) {
+	i, ok := m.columns[name]
+	if !ok {
+		return "", 0, &ParseError{Line: 1, Err: fmt.Errorf("missing column %s", name)}
+	}
+
+	s, err := field(line, record, i)
+
+	return s, i + 1, err
+}
+
+// parse maps a record into CurrencyExchangeRate, validating every field.
+func (m *columnMapping) parse(line int, record []string) (CurrencyExchangeRate, error) {
+	currency, column, err := m.column(line, record, headerCurrency)
+	if err != nil {
+		return CurrencyExchangeRate{}, err
+	}
+
+	if !currencyPattern.MatchString(currency) {
+		return CurrencyExchangeRate{}, &ParseError{Line: line, Column: column, Err: fmt.Errorf("invalid currency %q", currency)}
+	}
+
+	exchangeRate := CurrencyExchangeRate{Currency: Currency(currency)}
+
+	sides := make(map[Side]bool)
+
+	for _, block := range m.blocks {
+		marker, err := field(line, record, block.marker)
+		if err != nil {
+			return CurrencyExchangeRate{}, err
+		}
+
+		var side Side
+
+		switch marker {
+		case markerBuying, englishHeaders[markerBuying]:
+			side = SideBuying
+		case markerSelling, englishHeaders[markerSelling]:
+			side = SideSelling
+		default:
+			return CurrencyExchangeRate{}, &ParseError{Line: line, Column: block.marker + 1, Err: fmt.Errorf("invalid side %q", marker)}
+		}
+
+		if sides[side] {
+			return CurrencyExchangeRate{}, &ParseError{Line: line, Column: block.marker + 1, Err: fmt.Errorf("duplicate side %s", marker)}
+		}
+		sides[side] = true
+
+		for i, rate := range exchangeRate.rates(side) {
+			s, err := field(line, record, block.columns[i])
+			if err != nil {
+				return CurrencyExchangeRate{}, err
+			}
+
+			if !ratePattern.MatchString(s) {
+				return CurrencyExchangeRate{}, &ParseError{Line: line, Column: block.columns[i] + 1, Err: fmt.Errorf("invalid rate %q", s)}
+			}
+
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return CurrencyExchangeRate{}, &ParseError{Line: line, Column: block.columns[i] + 1, Err: fmt.Errorf("invalid rate %q", s)}
+			}
+
+			*rate = Rate(f)
+			exchangeRate.setText(side, i, s)
+		}
+	}
+
+	return exchangeRate, nil
+}
+
+// rates returns the fields of the rates of the given side in the order of rateHeaders.
+func (r *CurrencyExchangeRate) rates(side Side) []*Rate {
+	switch side {
+	case SideBuying:
+		return []*Rate{
+			&r.BuyingCash, &r.BuyingSpot,
+			&r.BuyingForward10Days, &r.BuyingForward30Days, &r.BuyingForward60Days, &r.BuyingForward90Days,
+			&r.BuyingForward120Days, &r.BuyingForward150Days, &r.BuyingForward180Days,
+		}
+
+	case SideSelling:
+		return []*Rate{
+			&r.SellingCash, &r.SellingSpot,
+			&r.SellingForward10Days, &r.SellingForward30Days, &r.SellingForward60Days, &r.SellingForward90Days,
+			&r.SellingForward120Days, &r.SellingForward150Days, &r.SellingForward180Days,
+		}
+
+	default:
+		return nil
+	}
+}
+
+// readCSV reads a rate file one record at a time, resolving the column mapping from the header, and calls fn with
+// every record after the header along with its line. fn is never called for an empty file.
+func readCSV(reader io.Reader, fn func(mapping *columnMapping, line int, record []string) error) error {
+	var mapping *columnMapping
+
+	return readRecords(reader, func(line int, record []string) error {
+		if mapping == nil {
+			var err error
+			mapping, err = newColumnMapping(record)
+			return err
+		}
+
+		return fn(mapping, line, record)
+	})
+}
+
+// readRecords reads a CSV file one record at a time and calls fn with every record, including the header, along with
+// its line. The record is reused by the next call.
+func readRecords(reader io.Reader, fn func(line int, record []string) error) error {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV file: %w", err)
+		}
+
+		if err := fn(line, record); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build go1.18
+// +build go1.18
+
+package twfxr_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mkfsn/twfxr"
+)
+
+func FuzzParse(f *testing.F) {
+	f.Add(ExchangeRatePage)
+	f.Add(testHeader + "\n" + testUSDRecord + "\n")
+	f.Add(strings.Replace(testHeader, "幣別", "Currency", 1) + "\n" + testUSDRecord + "\n")
+	f.Add(testHeader + "\n" + strings.Replace(testUSDRecord, "27.84500", "-", 1) + "\n")
+	f.Add(testHeader + "\n" + strings.Replace(testUSDRecord, "27.84500", "NaN", 1) + "\n")
+	f.Add(testHeader + "\n" + strings.Replace(testUSDRecord, "27.84500", "+Inf", 1) + "\n")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		currencies, _, err := twfxr.Parse(strings.NewReader(input), "ExchangeRate@202108290526.csv")
+		if err != nil {
+			var parseErr *twfxr.ParseError
+			if errors.As(err, &parseErr) && parseErr.Line < 1 {
+				t.Fatalf("invalid line of %v", err)
+			}
+			return
+		}
+
+		for currency, rate := range currencies {
+			if currency != rate.Currency {
+				t.Fatalf("%s is keyed by %s", rate.Currency, currency)
+			}
+
+			for _, side := range []twfxr.Side{twfxr.SideBuying, twfxr.SideSelling} {
+				cash, _ := rate.Cash(side)
+				checkRate(t, fmt.Sprintf("%s %s cash", currency, side), cash)
+
+				curve := rate.ForwardCurve(side)
+				checkRate(t, fmt.Sprintf("%s %s spot", currency, side), float64(curve.Spot))
+				for tenor, forward := range curve.Forwards {
+					checkRate(t, fmt.Sprintf("%s %s %d days forward", currency, side, tenor), float64(forward))
+				}
+			}
+		}
+	})
+}
+
+// checkRate fails the test unless the parsed rate is finite and not negative.
+func checkRate(t *testing.T, name string, rate float64) {
+	t.Helper()
+
+	if math.IsNaN(rate) || math.IsInf(rate, 0) || rate < 0 {
+		t.Fatalf("invalid %s rate %v", name, rate)
+	}
+}
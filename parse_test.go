@@ -0,0 +1,143 @@
+package twfxr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testHeader = "幣別,匯率,現金,即期,遠期10天,遠期30天,遠期60天,遠期90天,遠期120天,遠期150天,遠期180天," +
+		"匯率,現金,即期,遠期10天,遠期30天,遠期60天,遠期90天,遠期120天,遠期150天,遠期180天"
+	testUSDRecord = "USD,本行買入,27.52000,27.84500,27.86500,27.86500,27.86000,27.85500,27.85000,27.84100,27.83400," +
+		"本行賣出,28.19000,27.99500,27.97100,27.97100,27.97100,27.97000,27.97000,27.96900,27.96700,"
+)
+
+func TestParseColumnMapping(t *testing.T) {
+	expected, _, err := twfxr.Parse(strings.NewReader(ExchangeRatePage), "ExchangeRate@202108290526.csv")
+	assert.NoError(t, err)
+
+	testCases := map[string]string{
+		"without BOM": testHeader + "\r\n" + testUSDRecord + "\r\n",
+		"with BOM":    "\ufeff" + testHeader + "\r\n" + testUSDRecord + "\r\n",
+		"selling block first": "幣別,匯率,現金,即期,遠期10天,遠期30天,遠期60天,遠期90天,遠期120天,遠期150天,遠期180天," +
+			"匯率,現金,即期,遠期10天,遠期30天,遠期60天,遠期90天,遠期120天,遠期150天,遠期180天\n" +
+			"USD,本行賣出,28.19000,27.99500,27.97100,27.97100,27.97100,27.97000,27.97000,27.96900,27.96700," +
+			"本行買入,27.52000,27.84500,27.86500,27.86500,27.86000,27.85500,27.85000,27.84100,27.83400\n",
+		"english": "Currency,Rate,Cash,Spot,Forward-10Days,Forward-30Days,Forward-60Days,Forward-90Days," +
+			"Forward-120Days,Forward-150Days,Forward-180Days,Rate,Cash,Spot,Forward-10Days,Forward-30Days," +
+			"Forward-60Days,Forward-90Days,Forward-120Days,Forward-150Days,Forward-180Days\n" +
+			"USD,Buying,27.52000,27.84500,27.86500,27.86500,27.86000,27.85500,27.85000,27.84100,27.83400," +
+			"Selling,28.19000,27.99500,27.97100,27.97100,27.97100,27.97000,27.97000,27.96900,27.96700,\n",
+		"reordered columns": "匯率,即期,現金,遠期180天,遠期150天,遠期120天,遠期90天,遠期60天,遠期30天,遠期10天,幣別," +
+			"匯率,現金,即期,遠期10天,遠期30天,遠期60天,遠期90天,遠期120天,遠期150天,遠期180天,備註\n" +
+			"本行買入,27.84500,27.52000,27.83400,27.84100,27.85000,27.85500,27.86000,27.86500,27.86500,USD," +
+			"本行賣出,28.19000,27.99500,27.97100,27.97100,27.97100,27.97000,27.97000,27.96900,27.96700,\n",
+	}
+
+	for name, input := range testCases {
+		t.Run(name, func(t *testing.T) {
+			currencies, _, err := twfxr.Parse(strings.NewReader(input), "ExchangeRate@202108290526.csv")
+			assert.NoError(t, err)
+			assert.Equal(t, map[twfxr.Currency]twfxr.CurrencyExchangeRate{
+				twfxr.CurrencyUSD: expected[twfxr.CurrencyUSD],
+			}, currencies)
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	type wants struct {
+		line   int
+		column int
+	}
+
+	testCases := map[string]struct {
+		input string
+		wants wants
+	}{
+		"missing column": {
+			input: strings.Replace(testHeader, "遠期60天", "遠期45天", 1) + "\n" + testUSDRecord + "\n",
+			wants: wants{line: 1},
+		},
+		"missing currency column": {
+			input: strings.Replace(testHeader, "幣別", "Code", 1) + "\n" + testUSDRecord + "\n",
+			wants: wants{line: 1},
+		},
+		"short row": {
+			input: testHeader + "\n" + testUSDRecord + "\nJPY,本行買入,0.24490,0.25190\n",
+			wants: wants{line: 3, column: 5},
+		},
+		"invalid rate": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "27.84500", "-", 1) + "\n",
+			wants: wants{line: 2, column: 4},
+		},
+		"negative rate": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "27.84500", "-27.84500", 1) + "\n",
+			wants: wants{line: 2, column: 4},
+		},
+		"NaN rate": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "27.52000", "NaN", 1) + "\n",
+			wants: wants{line: 2, column: 3},
+		},
+		"infinite rate": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "27.84500", "+Inf", 1) + "\n",
+			wants: wants{line: 2, column: 4},
+		},
+		"exponent rate": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "27.84500", "1e1", 1) + "\n",
+			wants: wants{line: 2, column: 4},
+		},
+		"hexadecimal rate": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "27.84500", "0x1p4", 1) + "\n",
+			wants: wants{line: 2, column: 4},
+		},
+		"empty rate": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "27.99500", "", 1) + "\n",
+			wants: wants{line: 2, column: 14},
+		},
+		"invalid side": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "本行賣出", "本行", 1) + "\n",
+			wants: wants{line: 2, column: 12},
+		},
+		"invalid currency": {
+			input: testHeader + "\n" + strings.Replace(testUSDRecord, "USD", "", 1) + "\n",
+			wants: wants{line: 2, column: 1},
+		},
+		"duplicate currency": {
+			input: testHeader + "\n" + testUSDRecord + "\n" + testUSDRecord + "\n",
+			wants: wants{line: 3},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := twfxr.Parse(strings.NewReader(tc.input), "ExchangeRate@202108290526.csv")
+
+			var parseErr *twfxr.ParseError
+			if assert.ErrorAs(t, err, &parseErr) {
+				assert.Equal(t, tc.wants.line, parseErr.Line)
+				assert.Equal(t, tc.wants.column, parseErr.Column)
+			}
+		})
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	for _, filename := range []string{"", "rates.csv", "ExchangeRate@.csv", "ExchangeRate@2021082905.csv", "ExchangeRate@202113290526.csv"} {
+		_, _, err := twfxr.Parse(strings.NewReader(ExchangeRatePage), filename)
+		assert.Error(t, err, filename)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := twfxr.Parse(strings.NewReader(ExchangeRatePage), "ExchangeRate@202108290526.csv"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package twfxr
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Rate is a board rate in TWD per unit of the foreign currency.
+//
+// The bank quotes 0.00000 for the rates it does not offer, e.g. the cash rates of ZAR or the spot rates of KRW, so the
+// zero Rate means the rate is not offered. Use Valid or Float64 instead of using the value directly to avoid silently
+// multiplying by zero.
+type Rate float64
+
+// Valid reports whether the rate is offered by the bank.
+func (r Rate) Valid() bool {
+	return r != 0
+}
+
+// Float64 returns the rate and whether it is offered by the bank.
+func (r Rate) Float64() (float64, bool) {
+	return float64(r), r.Valid()
+}
+
+// MarshalJSON encodes a rate not offered by the bank as null.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	if !r.Valid() {
+		return []byte("null"), nil
+	}
+
+	return strconv.AppendFloat(nil, float64(r), 'f', -1, 64), nil
+}
+
+// UnmarshalJSON decodes null as a rate not offered by the bank.
+func (r *Rate) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		*r = 0
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return err
+	}
+
+	*r = Rate(f)
+
+	return nil
+}
+
+// Spot returns the spot rate of the given side and whether it is offered by the bank.
+func (r CurrencyExchangeRate) Spot(side Side) (float64, bool) {
+	switch side {
+	case SideBuying:
+		return r.BuyingSpot.Float64()
+	case SideSelling:
+		return r.SellingSpot.Float64()
+	default:
+		return 0, false
+	}
+}
+
+// Cash returns the cash rate of the given side and whether it is offered by the bank.
+func (r CurrencyExchangeRate) Cash(side Side) (float64, bool) {
+	switch side {
+	case SideBuying:
+		return r.BuyingCash.Float64()
+	case SideSelling:
+		return r.SellingCash.Float64()
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,51 @@
+package twfxr_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateJSON(t *testing.T) {
+	exchangeRate := twfxr.CurrencyExchangeRate{
+		Currency:    "ZAR",
+		BuyingSpot:  1.85100,
+		SellingSpot: 1.94100,
+	}
+
+	b, err := json.Marshal(exchangeRate)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"Buying-Cash":null`)
+	assert.Contains(t, string(b), `"Buying-Spot":1.851`)
+
+	var decoded twfxr.CurrencyExchangeRate
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, exchangeRate, decoded)
+}
+
+func TestCurrencyExchangeRateSpotAndCash(t *testing.T) {
+	exchangeRate := twfxr.CurrencyExchangeRate{
+		Currency:    "ZAR",
+		BuyingSpot:  1.85100,
+		SellingSpot: 1.94100,
+	}
+
+	rate, ok := exchangeRate.Spot(twfxr.SideBuying)
+	assert.True(t, ok)
+	assert.Equal(t, 1.851, rate)
+
+	rate, ok = exchangeRate.Spot(twfxr.SideSelling)
+	assert.True(t, ok)
+	assert.Equal(t, 1.941, rate)
+
+	_, ok = exchangeRate.Cash(twfxr.SideBuying)
+	assert.False(t, ok)
+
+	_, ok = exchangeRate.Cash(twfxr.SideSelling)
+	assert.False(t, ok)
+
+	_, ok = exchangeRate.Spot(twfxr.SideAuto)
+	assert.False(t, ok)
+}
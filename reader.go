@@ -0,0 +1,136 @@
+package twfxr
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// RateReader reads the board rates of a daily rate file one currency at a time, in the order published by the bank.
+//
+//	for r.Next() {
+//		rate := r.Rate()
+//		...
+//	}
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+type RateReader struct {
+	r       *csv.Reader
+	closer  io.Closer
+	mapping *columnMapping
+	line    int
+	seen    map[Currency]bool
+
+	rate CurrencyExchangeRate
+	err  error
+}
+
+// NewRateReader returns a RateReader reading a daily rate file from r.
+func NewRateReader(r io.Reader) *RateReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	return &RateReader{r: reader, seen: make(map[Currency]bool)}
+}
+
+// Next advances to the next currency, which is then available through Rate. It returns false at the end of the file
+// or on the first error, which is then available through Err.
+func (r *RateReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	if r.readHeader(); r.err != nil {
+		return false
+	}
+
+	record, err := r.read()
+	if err != nil {
+		r.setErr(err)
+		return false
+	}
+
+	rate, err := r.mapping.parse(r.line, record)
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	currency := rate.Currency
+	if r.seen[currency] {
+		r.err = &ParseError{Line: r.line, Err: fmt.Errorf("duplicate currency %s", currency)}
+		return false
+	}
+	r.seen[currency] = true
+
+	r.rate = rate
+
+	return true
+}
+
+// readHeader reads the header and resolves the column mapping unless the header is already read. The error is
+// available through Err.
+func (r *RateReader) readHeader() {
+	if r.mapping != nil || r.err != nil {
+		return
+	}
+
+	header, err := r.read()
+	if err != nil {
+		r.setErr(err)
+		return
+	}
+
+	r.mapping, r.err = newColumnMapping(header)
+}
+
+// language returns the language of the header, or empty if the header is not read yet.
+func (r *RateReader) language() string {
+	if r.mapping == nil {
+		return ""
+	}
+	return r.mapping.language
+}
+
+// Rate returns the rates of the currency read by the last call to Next.
+func (r *RateReader) Rate() CurrencyExchangeRate {
+	return r.rate
+}
+
+// Err returns the first error encountered by Next, or nil at the end of the file.
+func (r *RateReader) Err() error {
+	if r.err == io.EOF {
+		return nil
+	}
+	return r.err
+}
+
+// Close closes the underlying file of a RateReader returned by Client.OpenCurrencyExchangeRates or OpenFile. It is a
+// no-op for a RateReader returned by NewRateReader.
+func (r *RateReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+func (r *RateReader) read() ([]string, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	r.line++
+
+	return record, nil
+}
+
+// setErr records the error of reading the file, where io.EOF is the end of the file rather than an error.
+func (r *RateReader) setErr(err error) {
+	if err == io.EOF {
+		r.err = err
+		return
+	}
+	r.err = fmt.Errorf("failed to read CSV file: %w", err)
+}
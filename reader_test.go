@@ -0,0 +1,118 @@
+package twfxr_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRateReader(t *testing.T) {
+	expected, _, err := twfxr.Parse(strings.NewReader(ExchangeRatePage), "ExchangeRate@202108290526.csv")
+	assert.NoError(t, err)
+
+	r := twfxr.NewRateReader(strings.NewReader(ExchangeRatePage))
+
+	var currencies []twfxr.Currency
+	for r.Next() {
+		rate := r.Rate()
+		assert.Equal(t, expected[twfxr.Currency(rate.Currency)], rate)
+		currencies = append(currencies, twfxr.Currency(rate.Currency))
+	}
+	assert.NoError(t, r.Err())
+	assert.NoError(t, r.Close())
+
+	assert.Equal(t, []twfxr.Currency{
+		twfxr.CurrencyUSD, twfxr.CurrencyHKD, twfxr.CurrencyGBP, twfxr.CurrencyAUD, twfxr.CurrencyCAD,
+		twfxr.CurrencySGD, twfxr.CurrencyCHF, twfxr.CurrencyJPY, twfxr.CurrencyZAR, twfxr.CurrencySEK,
+		twfxr.CurrencyNZD, twfxr.CurrencyTHB, twfxr.CurrencyPHP, twfxr.CurrencyIDR, twfxr.CurrencyEUR,
+		twfxr.CurrencyKRW, twfxr.CurrencyVND, twfxr.CurrencyMYR, twfxr.CurrencyCNY,
+	}, currencies)
+	assert.False(t, r.Next())
+}
+
+func TestRateReaderError(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		read  int
+	}{
+		"When the header is malformed, Then no rate is read": {
+			input: strings.Replace(testHeader, "幣別", "Code", 1) + "\n" + testUSDRecord + "\n",
+			read:  0,
+		},
+		"When a record is malformed, Then the rates before it are read": {
+			input: testHeader + "\n" + testUSDRecord + "\nJPY,本行買入,0.24490,0.25190\n",
+			read:  1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := twfxr.NewRateReader(strings.NewReader(tc.input))
+
+			n := 0
+			for r.Next() {
+				n++
+			}
+
+			var parseErr *twfxr.ParseError
+			assert.ErrorAs(t, r.Err(), &parseErr)
+			assert.Equal(t, tc.read, n)
+		})
+	}
+}
+
+type readerSuite struct {
+	suite.Suite
+
+	transport *httpmock.MockTransport
+}
+
+func (suite *readerSuite) SetupTest() {
+	suite.transport = httpmock.NewMockTransport()
+	suite.transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/day",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, ExchangeRatePage)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+}
+
+func (suite *readerSuite) TestOpenCurrencyExchangeRates() {
+	for name, opts := range map[string][]twfxr.Option{
+		"without cache": nil,
+		"with cache":    {twfxr.WithCache(twfxr.NewMemoryCache())},
+	} {
+		suite.Run(name, func() {
+			client := twfxr.NewClient(append(opts, twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}))...)
+
+			r, metadata, err := client.OpenCurrencyExchangeRates(context.Background())
+			suite.Require().NoError(err)
+			defer r.Close()
+
+			suite.True(r.Next())
+			suite.Equal(twfxr.CurrencyUSD, r.Rate().Currency)
+			suite.Equal(twfxr.Rate(27.845), r.Rate().BuyingSpot)
+			suite.Equal(2021, metadata.QuotedAt.Year())
+			suite.Equal("https://rate.bot.com.tw/xrt/flcsv/0/day", metadata.SourceURL)
+			suite.Equal("zh-TW", metadata.Language)
+
+			n := 1
+			for r.Next() {
+				n++
+			}
+			suite.NoError(r.Err())
+			suite.Equal(19, n)
+		})
+	}
+}
+
+func TestReaderSuite(t *testing.T) {
+	suite.Run(t, new(readerSuite))
+}
@@ -0,0 +1,144 @@
+package twfxr
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries    = 3
+	defaultRetryBaseWait = 500 * time.Millisecond
+	defaultRetryMaxWait  = 10 * time.Second
+)
+
+// WithRetry sets how many times a failed download is retried, which defaults to 3. The client waits baseWait before
+// the first retry and doubles the wait before every subsequent retry up to maxWait, with a random jitter of up to half
+// of the wait. A Retry-After header sent by the bank takes precedence, unless it is longer than maxWait, in which case
+// the download fails immediately. Use WithRetry(0, 0, 0) to disable retries.
+func WithRetry(maxRetries int, baseWait, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBaseWait = baseWait
+		c.retryMaxWait = maxWait
+	}
+}
+
+// retryableError is a failed download which may succeed when retried, e.g. 503 Service Unavailable.
+type retryableError struct {
+	err error
+	// retryAfter is the wait requested by the Retry-After header, or 0 if there is none.
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// isRetryableStatus reports whether a response of the given status code may succeed when the request is retried.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date. It returns 0 when
+// the header is absent or malformed.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	t, err := http.ParseTime(header)
+	if err != nil {
+		return 0
+	}
+
+	if d := t.Sub(now); d > 0 {
+		return d
+	}
+
+	return 0
+}
+
+// retry calls fn until it succeeds, fails with an error which is not retryable, or the retries run out. The last error
+// is returned with the retryableError unwrapped.
+func (c *Client) retry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) {
+			return err
+		}
+
+		if attempt >= c.maxRetries || ctx.Err() != nil {
+			return retryable.err
+		}
+
+		wait := c.backoff(attempt)
+		if retryable.retryAfter > 0 {
+			if retryable.retryAfter > c.retryMaxWait {
+				return retryable.err
+			}
+			wait = retryable.retryAfter
+		}
+
+		if err := c.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for the given duration, or returns the error of ctx if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff returns the wait before the retry after the given number of attempts, i.e. baseWait * 2^attempt capped at
+// maxWait, reduced by a random jitter of up to half of it.
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retryBaseWait
+	for i := 0; i < attempt && wait < c.retryMaxWait; i++ {
+		wait *= 2
+	}
+
+	if wait > c.retryMaxWait {
+		wait = c.retryMaxWait
+	}
+
+	if wait <= 0 {
+		return 0
+	}
+
+	return wait - time.Duration(rand.Int63n(int64(wait)/2+1))
+}
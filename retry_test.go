@@ -0,0 +1,180 @@
+package twfxr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/suite"
+)
+
+const dayURL = "https://rate.bot.com.tw/xrt/flcsv/0/day"
+
+type retrySuite struct {
+	suite.Suite
+
+	transport *httpmock.MockTransport
+}
+
+func (suite *retrySuite) SetupTest() {
+	suite.transport = httpmock.NewMockTransport()
+}
+
+func (suite *retrySuite) newClient(maxRetries int, baseWait, maxWait time.Duration) *twfxr.Client {
+	return twfxr.NewClient(
+		twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}),
+		twfxr.WithRetry(maxRetries, baseWait, maxWait),
+	)
+}
+
+// respond registers the given responses of the daily rate file in order, where the last one is repeated.
+func (suite *retrySuite) respond(responses ...*http.Response) {
+	calls := 0
+	suite.transport.RegisterResponder(http.MethodGet, dayURL, func(req *http.Request) (*http.Response, error) {
+		i := calls
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		calls++
+
+		return httpmock.ResponderFromResponse(responses[i])(req)
+	})
+}
+
+func rateFileResponse() *http.Response {
+	resp := newResponse(http.StatusOK, ExchangeRatePage)
+	resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+	return resp
+}
+
+func errorPageResponse(status int, retryAfter string) *http.Response {
+	resp := newResponse(status, "<html><body>Service Unavailable</body></html>")
+	resp.Header.Set("Content-Type", "text/html; charset=utf-8")
+	if retryAfter != "" {
+		resp.Header.Set("Retry-After", retryAfter)
+	}
+	return resp
+}
+
+func (suite *retrySuite) TestRetryUntilSuccess() {
+	suite.respond(
+		errorPageResponse(http.StatusServiceUnavailable, ""),
+		errorPageResponse(http.StatusBadGateway, ""),
+		rateFileResponse(),
+	)
+
+	currencies, _, err := suite.newClient(3, time.Millisecond, 10*time.Millisecond).GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.Len(currencies, 19)
+	suite.Equal(3, suite.transport.GetTotalCallCount())
+}
+
+func (suite *retrySuite) TestRetryNetworkError() {
+	calls := 0
+	suite.transport.RegisterResponder(http.MethodGet, dayURL, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return rateFileResponse(), nil
+	})
+
+	_, _, err := suite.newClient(1, time.Millisecond, 10*time.Millisecond).GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.Equal(2, calls)
+}
+
+func (suite *retrySuite) TestErrors() {
+	testCases := map[string]struct {
+		response *http.Response
+		calls    int
+		err      error
+	}{
+		"When the bank keeps responding 503, Then the retries run out": {
+			response: errorPageResponse(http.StatusServiceUnavailable, ""),
+			calls:    3,
+			err:      twfxr.ErrUpstreamUnavailable,
+		},
+		"When the bank asks to retry later than the max wait, Then it is not retried": {
+			response: errorPageResponse(http.StatusTooManyRequests, "3600"),
+			calls:    1,
+			err:      twfxr.ErrUpstreamUnavailable,
+		},
+		"When the bank responds an HTML page, Then it is not retried": {
+			response: errorPageResponse(http.StatusOK, ""),
+			calls:    1,
+			err:      twfxr.ErrUnexpectedContentType,
+		},
+		"When the bank responds without Content-Disposition, Then it is not retried": {
+			response: newResponse(http.StatusOK, ExchangeRatePage),
+			calls:    1,
+			err:      twfxr.ErrUnexpectedContentType,
+		},
+		"When the bank responds 404, Then it is not retried": {
+			response: errorPageResponse(http.StatusNotFound, ""),
+			calls:    1,
+			err:      twfxr.ErrNotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			suite.SetupTest()
+			suite.respond(tc.response)
+
+			_, _, err := suite.newClient(2, time.Millisecond, 10*time.Millisecond).GetCurrencyExchangeRates(context.Background())
+			suite.ErrorIs(err, tc.err)
+			suite.Equal(tc.calls, suite.transport.GetTotalCallCount())
+		})
+	}
+}
+
+func (suite *retrySuite) TestUnexpectedStatus() {
+	suite.respond(errorPageResponse(http.StatusForbidden, ""))
+
+	_, _, err := suite.newClient(2, time.Millisecond, 10*time.Millisecond).GetCurrencyExchangeRates(context.Background())
+	suite.EqualError(err, "https://rate.bot.com.tw/xrt/flcsv/0/day: unexpected status 403")
+	suite.Equal(1, suite.transport.GetTotalCallCount())
+}
+
+func (suite *retrySuite) TestRetryAfter() {
+	suite.respond(errorPageResponse(http.StatusServiceUnavailable, "1"), rateFileResponse())
+
+	var waits []time.Duration
+
+	client := twfxr.NewClient(
+		twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}),
+		twfxr.WithRetry(1, time.Millisecond, 2*time.Second),
+		twfxr.WithSleep(func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		}),
+	)
+
+	_, _, err := client.GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+	suite.Equal([]time.Duration{time.Second}, waits)
+	suite.Equal(2, suite.transport.GetTotalCallCount())
+}
+
+func (suite *retrySuite) TestContextCanceledWhileWaiting() {
+	suite.respond(errorPageResponse(http.StatusServiceUnavailable, ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	_, _, err := suite.newClient(3, time.Minute, time.Minute).GetCurrencyExchangeRates(ctx)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Less(int64(time.Since(start)), int64(time.Second))
+	suite.Equal(1, suite.transport.GetTotalCallCount())
+}
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(retrySuite))
+}
@@ -0,0 +1,256 @@
+package twfxr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Snapshot is the board rates of the currencies in a daily rate file along with its metadata, in the order published
+// by the bank. A Snapshot is immutable, the rates returned by its methods are copies.
+type Snapshot struct {
+	Metadata Metadata
+
+	rates []CurrencyExchangeRate
+	index map[Currency]int
+}
+
+// NewSnapshot returns a Snapshot of the given rates in the given order. An error is returned when a currency appears
+// more than once.
+func NewSnapshot(rates []CurrencyExchangeRate, metadata Metadata) (Snapshot, error) {
+	s := Snapshot{
+		Metadata: metadata,
+		rates:    make([]CurrencyExchangeRate, 0, len(rates)),
+		index:    make(map[Currency]int, len(rates)),
+	}
+
+	for _, rate := range rates {
+		if err := s.add(rate); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Snapshot) add(rate CurrencyExchangeRate) error {
+	if s.index == nil {
+		s.index = make(map[Currency]int)
+	}
+
+	currency := rate.Currency
+	if _, ok := s.index[currency]; ok {
+		return fmt.Errorf("duplicate currency %s", currency)
+	}
+
+	s.index[currency] = len(s.rates)
+	s.rates = append(s.rates, rate)
+
+	return nil
+}
+
+// Len returns the number of currencies.
+func (s Snapshot) Len() int {
+	return len(s.rates)
+}
+
+// Get returns the rates of the given currency and whether it is in the snapshot.
+func (s Snapshot) Get(currency Currency) (CurrencyExchangeRate, bool) {
+	i, ok := s.index[currency]
+	if !ok {
+		return CurrencyExchangeRate{}, false
+	}
+	return s.rates[i], true
+}
+
+// Currencies returns the currencies in the order published by the bank.
+func (s Snapshot) Currencies() []Currency {
+	currencies := make([]Currency, 0, len(s.rates))
+	for _, rate := range s.rates {
+		currencies = append(currencies, rate.Currency)
+	}
+	return currencies
+}
+
+// Rates returns the rates of every currency in the order published by the bank.
+func (s Snapshot) Rates() []CurrencyExchangeRate {
+	return append([]CurrencyExchangeRate(nil), s.rates...)
+}
+
+// Map returns the rates keyed by currency, e.g. for Convert.
+func (s Snapshot) Map() map[Currency]CurrencyExchangeRate {
+	m := make(map[Currency]CurrencyExchangeRate, len(s.rates))
+	for _, rate := range s.rates {
+		m[rate.Currency] = rate
+	}
+	return m
+}
+
+// Filter returns a Snapshot of only the given currencies, still in the order published by the bank. The currencies
+// not in the snapshot are ignored.
+func (s Snapshot) Filter(currencies ...Currency) Snapshot {
+	selected := make(map[Currency]bool, len(currencies))
+	for _, currency := range currencies {
+		selected[currency] = true
+	}
+
+	filtered := Snapshot{Metadata: s.Metadata}
+	for _, rate := range s.rates {
+		if selected[rate.Currency] {
+			_ = filtered.add(rate)
+		}
+	}
+
+	return filtered
+}
+
+// Each calls fn with the rates of every currency in the order published by the bank.
+func (s Snapshot) Each(fn func(CurrencyExchangeRate)) {
+	for _, rate := range s.rates {
+		fn(rate)
+	}
+}
+
+type snapshotJSON struct {
+	Metadata Metadata
+	Rates    []CurrencyExchangeRate
+}
+
+// MarshalJSON encodes the snapshot as an object of the metadata and an array of the rates in the order published by
+// the bank.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	rates := s.rates
+	if rates == nil {
+		rates = []CurrencyExchangeRate{}
+	}
+
+	return json.Marshal(snapshotJSON{Metadata: s.Metadata, Rates: rates})
+}
+
+// UnmarshalJSON decodes a snapshot encoded by MarshalJSON.
+func (s *Snapshot) UnmarshalJSON(b []byte) error {
+	var v snapshotJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	snapshot, err := NewSnapshot(v.Rates, v.Metadata)
+	if err != nil {
+		return err
+	}
+
+	*s = snapshot
+
+	return nil
+}
+
+// MarshalText encodes the rates in the format of the daily rate file of the bank in the language of Metadata.Language,
+// which can be parsed by Parse. The metadata is not encoded except what the file carries.
+func (s Snapshot) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("\ufeff")
+	buf.WriteString(strings.Join(dailyHeader(s.Metadata.Language), ","))
+	buf.WriteString("\r\n")
+
+	w := csv.NewWriter(&buf)
+	w.UseCRLF = true
+
+	for _, rate := range s.rates {
+		// The records of the bank end with an empty field.
+		if err := w.Write(append(rate.record(s.Metadata.Language), "")); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText decodes the rates in the format of the daily rate file of the bank. As the quote time is carried by
+// the filename, only Metadata.SHA256 and Metadata.Language are set.
+func (s *Snapshot) UnmarshalText(b []byte) error {
+	r := NewRateReader(bytes.NewReader(b))
+
+	snapshot, err := readSnapshot(r, Metadata{})
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(b)
+	snapshot.Metadata.SHA256 = hex.EncodeToString(sum[:])
+
+	*s = snapshot
+
+	return nil
+}
+
+// dailyHeader returns the header of the daily rate file of the bank in the given language.
+func dailyHeader(lang string) []string {
+	header := []string{localizeHeader(headerCurrency, lang)}
+	for i := 0; i < 2; i++ {
+		header = append(header, localizeHeader(headerMarker, lang))
+		for _, h := range rateHeaders {
+			header = append(header, localizeHeader(h, lang))
+		}
+	}
+	return header
+}
+
+// ParseSnapshot is Parse returning a Snapshot.
+func ParseSnapshot(r io.Reader, filename string) (Snapshot, error) {
+	metadata, err := parseMetadata(filename)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	hash := sha256.New()
+
+	snapshot, err := readSnapshot(NewRateReader(io.TeeReader(r, hash)), metadata)
+	if err != nil {
+		return Snapshot{Metadata: metadata}, err
+	}
+
+	snapshot.Metadata.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	return snapshot, nil
+}
+
+// ParseSnapshotFile is ParseFile returning a Snapshot.
+func ParseSnapshotFile(path string) (Snapshot, error) {
+	var snapshot Snapshot
+
+	err := parseFile(path, func(r io.Reader, filename string) (err error) {
+		snapshot, err = ParseSnapshot(r, filename)
+		return err
+	})
+
+	return snapshot, err
+}
+
+// readSnapshot reads every currency of the given RateReader into a Snapshot.
+func readSnapshot(r *RateReader, metadata Metadata) (Snapshot, error) {
+	snapshot := Snapshot{Metadata: metadata}
+
+	for r.Next() {
+		// RateReader already rejects duplicate currencies.
+		_ = snapshot.add(r.Rate())
+	}
+
+	if err := r.Err(); err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot.Metadata.Language = r.language()
+
+	return snapshot, nil
+}
@@ -0,0 +1,161 @@
+package twfxr_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/suite"
+)
+
+type snapshotSuite struct {
+	suite.Suite
+
+	snapshot twfxr.Snapshot
+}
+
+func (suite *snapshotSuite) SetupTest() {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/day",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, ExchangeRatePage)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+
+	client := twfxr.NewClient(twfxr.WithHTTPClient(&http.Client{Transport: transport}))
+
+	snapshot, err := client.GetSnapshot(context.Background())
+	suite.Require().NoError(err)
+
+	suite.snapshot = snapshot
+}
+
+func (suite *snapshotSuite) TestQuery() {
+	suite.Equal(19, suite.snapshot.Len())
+	suite.Equal("ExchangeRate@202108290526.csv", suite.snapshot.Metadata.Filename)
+
+	currencies := suite.snapshot.Currencies()
+	suite.Equal([]twfxr.Currency{twfxr.CurrencyUSD, twfxr.CurrencyHKD, twfxr.CurrencyGBP}, currencies[:3])
+	suite.Equal(twfxr.CurrencyCNY, currencies[18])
+
+	usd, ok := suite.snapshot.Get(twfxr.CurrencyUSD)
+	suite.True(ok)
+	suite.Equal(twfxr.Rate(27.845), usd.BuyingSpot)
+
+	_, ok = suite.snapshot.Get(twfxr.CurrencyTWD)
+	suite.False(ok)
+
+	var each []twfxr.Currency
+	suite.snapshot.Each(func(rate twfxr.CurrencyExchangeRate) {
+		each = append(each, rate.Currency)
+	})
+	suite.Equal(currencies, each)
+
+	m := suite.snapshot.Map()
+	suite.Len(m, 19)
+	suite.Equal(usd, m[twfxr.CurrencyUSD])
+}
+
+func (suite *snapshotSuite) TestFilter() {
+	filtered := suite.snapshot.Filter(twfxr.CurrencyJPY, twfxr.CurrencyUSD, twfxr.CurrencyTWD)
+	suite.Equal([]twfxr.Currency{twfxr.CurrencyUSD, twfxr.CurrencyJPY}, filtered.Currencies())
+	suite.Equal(suite.snapshot.Metadata, filtered.Metadata)
+
+	// Filtering does not change the original snapshot.
+	suite.Equal(19, suite.snapshot.Len())
+
+	suite.Zero(suite.snapshot.Filter().Len())
+}
+
+func (suite *snapshotSuite) TestJSON() {
+	b, err := json.Marshal(suite.snapshot)
+	suite.NoError(err)
+
+	var decoded twfxr.Snapshot
+	suite.NoError(json.Unmarshal(b, &decoded))
+	suite.Equal(suite.snapshot.Rates(), decoded.Rates())
+	suite.Equal(suite.snapshot.Currencies(), decoded.Currencies())
+	suite.True(suite.snapshot.Metadata.QuotedAt.Equal(decoded.Metadata.QuotedAt))
+	suite.Equal(suite.snapshot.Metadata.SHA256, decoded.Metadata.SHA256)
+
+	b, err = json.Marshal(twfxr.Snapshot{})
+	suite.NoError(err)
+	suite.Contains(string(b), `"Rates":[]`)
+}
+
+func (suite *snapshotSuite) TestJSONNewCurrency() {
+	page := ExchangeRatePage + strings.Replace(strings.Split(ExchangeRatePage, "\n")[1], "USD", "XYZ", 1) + "\n"
+
+	snapshot, err := twfxr.ParseSnapshot(strings.NewReader(page), "ExchangeRate@202108290526.csv")
+	suite.Require().NoError(err)
+	suite.Equal(20, snapshot.Len())
+
+	b, err := json.Marshal(snapshot)
+	suite.NoError(err)
+
+	var decoded twfxr.Snapshot
+	suite.NoError(json.Unmarshal(b, &decoded))
+	suite.Equal(snapshot.Rates(), decoded.Rates())
+}
+
+func (suite *snapshotSuite) TestText() {
+	b, err := suite.snapshot.MarshalText()
+	suite.NoError(err)
+
+	// The bank's file is reproduced byte for byte.
+	suite.Equal(ExchangeRatePage, string(b))
+
+	var decoded twfxr.Snapshot
+	suite.NoError(decoded.UnmarshalText(b))
+	suite.Equal(suite.snapshot.Rates(), decoded.Rates())
+	suite.Equal(suite.snapshot.Metadata.SHA256, decoded.Metadata.SHA256)
+	suite.Equal("zh-TW", decoded.Metadata.Language)
+}
+
+func (suite *snapshotSuite) TestEnglish() {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/1/day",
+		func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusOK, ExchangeRatePageEn)
+			resp.Header.Add("Content-Disposition", `attachment; filename="ExchangeRate@202108290526.csv"`)
+			return resp, nil
+		},
+	)
+
+	client := twfxr.NewClient(
+		twfxr.WithHTTPClient(&http.Client{Transport: transport}),
+		twfxr.WithLanguage(twfxr.LanguageEn),
+	)
+
+	snapshot, err := client.GetSnapshot(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(twfxr.LanguageEn, snapshot.Metadata.Language)
+	suite.True(suite.snapshot.Metadata.QuotedAt.Equal(snapshot.Metadata.QuotedAt))
+
+	// The rates are the same in both languages.
+	suite.Equal(suite.snapshot.Rates(), snapshot.Rates())
+
+	// The bank's file is reproduced byte for byte in the language of the snapshot.
+	b, err := snapshot.MarshalText()
+	suite.NoError(err)
+	suite.Equal(ExchangeRatePageEn, string(b))
+}
+
+func (suite *snapshotSuite) TestNewSnapshot() {
+	_, err := twfxr.NewSnapshot([]twfxr.CurrencyExchangeRate{{Currency: "USD"}, {Currency: "USD"}}, twfxr.Metadata{})
+	suite.Error(err)
+
+	snapshot, err := twfxr.NewSnapshot([]twfxr.CurrencyExchangeRate{{Currency: "JPY"}, {Currency: "USD"}}, twfxr.Metadata{})
+	suite.NoError(err)
+	suite.Equal([]twfxr.Currency{twfxr.CurrencyJPY, twfxr.CurrencyUSD}, snapshot.Currencies())
+}
+
+func TestSnapshotSuite(t *testing.T) {
+	suite.Run(t, new(snapshotSuite))
+}
@@ -1,16 +1,11 @@
 package twfxr
 
 import (
-	"bytes"
 	"context"
-	"encoding/csv"
-	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
-	"io/ioutil"
-	"mime"
-	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -18,135 +13,122 @@ var (
 	asiaTaipei = time.FixedZone("UTC+8", 8*60*60)
 )
 
-const (
-	csvFileURL = "https://rate.bot.com.tw/xrt/flcsv/0/day"
+var (
+	ErrNotFound = errors.New("not found")
+	// ErrUpstreamUnavailable is returned when the bank keeps failing to serve the rate files, e.g. with 503 Service
+	// Unavailable, after the retries configured by WithRetry.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrUnexpectedContentType is returned when the bank responds with something other than a rate file, e.g. an HTML
+	// page of maintenance.
+	ErrUnexpectedContentType = errors.New("unexpected content type")
 )
 
 var (
-	ErrNotFound = errors.New("not found")
+	defaultClient = NewClient()
 )
 
-type Metadata struct {
-	QuotedAt time.Time
+// GetCurrencyExchangeRate is a wrapper of Client.GetCurrencyExchangeRate using the default client.
+func GetCurrencyExchangeRate(ctx context.Context, currency Currency) (CurrencyExchangeRate, Metadata, error) {
+	return defaultClient.GetCurrencyExchangeRate(ctx, currency)
 }
 
-func GetCurrencyExchangeRate(ctx context.Context, currency Currency) (CurrencyExchangeRate, Metadata, error) {
-	results, metadata, err := GetCurrencyExchangeRates(ctx)
-	if err != nil {
-		return CurrencyExchangeRate{}, metadata, err
-	}
+// GetCurrencyExchangeRates is a wrapper of Client.GetCurrencyExchangeRates using the default client.
+func GetCurrencyExchangeRates(ctx context.Context) (map[Currency]CurrencyExchangeRate, Metadata, error) {
+	return defaultClient.GetCurrencyExchangeRates(ctx)
+}
 
-	v, ok := results[currency]
-	if !ok {
-		return CurrencyExchangeRate{}, metadata, fmt.Errorf("no such currency: %w", ErrNotFound)
-	}
+// OpenCurrencyExchangeRates is a wrapper of Client.OpenCurrencyExchangeRates using the default client.
+func OpenCurrencyExchangeRates(ctx context.Context) (*RateReader, Metadata, error) {
+	return defaultClient.OpenCurrencyExchangeRates(ctx)
+}
 
-	return v, metadata, nil
+// GetSnapshot is a wrapper of Client.GetSnapshot using the default client.
+func GetSnapshot(ctx context.Context) (Snapshot, error) {
+	return defaultClient.GetSnapshot(ctx)
 }
 
-func GetCurrencyExchangeRates(ctx context.Context) (map[Currency]CurrencyExchangeRate, Metadata, error) {
-	filename, data, err := getExchangeRateCSVFile(ctx)
-	if err != nil {
-		return nil, Metadata{}, err
-	}
+// GetSnapshotOn is a wrapper of Client.GetSnapshotOn using the default client.
+func GetSnapshotOn(ctx context.Context, date time.Time) (Snapshot, error) {
+	return defaultClient.GetSnapshotOn(ctx, date)
+}
 
-	metadata, err := parseMetadata(filename)
+// GetCurrencyExchangeRatesOn is a wrapper of Client.GetCurrencyExchangeRatesOn using the default client.
+func GetCurrencyExchangeRatesOn(ctx context.Context, date time.Time) (map[Currency]CurrencyExchangeRate, Metadata, error) {
+	return defaultClient.GetCurrencyExchangeRatesOn(ctx, date)
+}
+
+// Parse parses a daily board rate file downloaded from the bank, where filename is the original filename of the file,
+// e.g. ExchangeRate@202108290526.csv, which carries the quote time.
+func Parse(r io.Reader, filename string) (map[Currency]CurrencyExchangeRate, Metadata, error) {
+	snapshot, err := ParseSnapshot(r, filename)
 	if err != nil {
-		return nil, metadata, err
+		return nil, snapshot.Metadata, err
 	}
 
-	currencies, err := parseCSV(bytes.NewReader(data))
-
-	return currencies, metadata, err
+	return snapshot.Map(), snapshot.Metadata, nil
 }
 
-func getExchangeRateCSVFile(ctx context.Context) (filename string, data []byte, err error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, csvFileURL, nil)
-	if err != nil {
-		return "", nil, err
-	}
+// ParseFile parses a daily board rate file previously downloaded from the bank. The file must keep its original name,
+// e.g. ExchangeRate@202108290526.csv.
+func ParseFile(path string) (map[Currency]CurrencyExchangeRate, Metadata, error) {
+	var (
+		rates    map[Currency]CurrencyExchangeRate
+		metadata Metadata
+	)
 
-	client := http.DefaultClient
+	err := parseFile(path, func(r io.Reader, filename string) (err error) {
+		rates, metadata, err = Parse(r, filename)
+		return err
+	})
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", nil, err
-	}
-	defer resp.Body.Close()
+	return rates, metadata, err
+}
 
-	contentDisposition := resp.Header.Get("Content-Disposition")
-	_, params, err := mime.ParseMediaType(contentDisposition)
+// parseFile opens the file of the given path and calls parse with it along with its name, which carries the quote
+// time.
+func parseFile(path string, parse func(r io.Reader, filename string) error) error {
+	f, err := os.Open(path)
 	if err != nil {
-		return "", nil, err
+		return err
 	}
+	defer f.Close()
+
+	return parse(f, filepath.Base(path))
+}
 
-	data, err = ioutil.ReadAll(resp.Body)
+// OpenFile returns a RateReader of a daily board rate file previously downloaded from the bank. The file must keep its
+// original name, e.g. ExchangeRate@202108290526.csv. The caller must close the RateReader.
+func OpenFile(path string) (*RateReader, Metadata, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return "", nil, err
+		return nil, Metadata{}, err
 	}
 
-	return params["filename"], data, nil
-}
-
-func parseMetadata(filename string) (metadata Metadata, err error) {
-	// filename: ExchangeRate@202108280526.csv
-	metadata.QuotedAt, err = time.ParseInLocation("200601021504", filename[13:len(filename)-4], asiaTaipei)
+	r, metadata, err := openRateReader(f, filepath.Base(path))
 	if err != nil {
-		return metadata, fmt.Errorf("failed to parse filename: %w", err)
+		f.Close()
+		return nil, metadata, err
 	}
 
-	return metadata, nil
+	return r, metadata, nil
 }
 
-func parseCSV(reader io.Reader) (map[Currency]CurrencyExchangeRate, error) {
-	r := csv.NewReader(reader)
-	r.FieldsPerRecord = -1
-
-	records, err := r.ReadAll()
+// openRateReader returns a RateReader of the given file whose header is already read, so that the language of the
+// file is known.
+func openRateReader(rc io.ReadCloser, filename string) (*RateReader, Metadata, error) {
+	metadata, err := parseMetadata(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV file: %w", err)
+		return nil, metadata, err
 	}
 
-	currencies := make(map[Currency]CurrencyExchangeRate)
-
-	for _, record := range records[1:] {
-		data := map[string]json.RawMessage{
-			"Currency": json.RawMessage(fmt.Sprintf("%q", record[0])),
-			// Buying
-			"Buying-Cash":            json.RawMessage(fmt.Sprintf("%s", record[2])),
-			"Buying-Spot":            json.RawMessage(fmt.Sprintf("%s", record[3])),
-			"Buying-Forward-10Days":  json.RawMessage(fmt.Sprintf("%s", record[4])),
-			"Buying-Forward-30Days":  json.RawMessage(fmt.Sprintf("%s", record[5])),
-			"Buying-Forward-60Days":  json.RawMessage(fmt.Sprintf("%s", record[6])),
-			"Buying-Forward-90Days":  json.RawMessage(fmt.Sprintf("%s", record[7])),
-			"Buying-Forward-120Days": json.RawMessage(fmt.Sprintf("%s", record[8])),
-			"Buying-Forward-150Days": json.RawMessage(fmt.Sprintf("%s", record[9])),
-			"Buying-Forward-180Days": json.RawMessage(fmt.Sprintf("%s", record[10])),
-			// Selling
-			"Selling-Cash":            json.RawMessage(fmt.Sprintf("%s", record[12])),
-			"Selling-Spot":            json.RawMessage(fmt.Sprintf("%s", record[13])),
-			"Selling-Forward-10Days":  json.RawMessage(fmt.Sprintf("%s", record[14])),
-			"Selling-Forward-30Days":  json.RawMessage(fmt.Sprintf("%s", record[15])),
-			"Selling-Forward-60Days":  json.RawMessage(fmt.Sprintf("%s", record[16])),
-			"Selling-Forward-90Days":  json.RawMessage(fmt.Sprintf("%s", record[17])),
-			"Selling-Forward-120Days": json.RawMessage(fmt.Sprintf("%s", record[18])),
-			"Selling-Forward-150Days": json.RawMessage(fmt.Sprintf("%s", record[19])),
-			"Selling-Forward-180Days": json.RawMessage(fmt.Sprintf("%s", record[20])),
-		}
-
-		b, err := json.Marshal(data)
-		if err != nil {
-			return nil, err
-		}
-
-		var exchangeRate CurrencyExchangeRate
-
-		if err := json.Unmarshal(b, &exchangeRate); err != nil {
-			return nil, err
-		}
-
-		currencies[Currency(record[0])] = exchangeRate
+	r := NewRateReader(rc)
+	r.closer = rc
+
+	r.readHeader()
+	if err := r.Err(); err != nil {
+		return nil, metadata, err
 	}
+	metadata.Language = r.language()
 
-	return currencies, nil
+	return r, metadata, nil
 }
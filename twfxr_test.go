@@ -2,8 +2,14 @@ package twfxr_test
 
 import (
 	"context"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
+	"io"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +22,21 @@ import (
 //go:embed testdata/ExchangeRate@202108290526.csv
 var ExchangeRatePage string
 
+//go:embed testdata/en/ExchangeRate@202108290526.csv
+var ExchangeRatePageEn string
+
+// newResponse returns a response whose body keeps returning io.EOF once fully read, like the body of a real response.
+// The bodies of httpmock.NewStringResponse rewind at the end instead.
+func newResponse(status int, body string) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status),
+		StatusCode:    status,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		Header:        http.Header{},
+		ContentLength: -1,
+	}
+}
+
 type twfxrSuite struct {
 	suite.Suite
 }
@@ -24,13 +45,29 @@ func (suite *twfxrSuite) SetupSuite() {
 	httpmock.Activate()
 	httpmock.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/day",
 		func(req *http.Request) (*http.Response, error) {
-			resp := httpmock.NewStringResponse(http.StatusOK, ExchangeRatePage)
+			resp := newResponse(http.StatusOK, ExchangeRatePage)
 			resp.Header.Add("Content-Disposition", ` attachment; filename="ExchangeRate@202108290526.csv"`)
 			return resp, nil
 		},
 	)
 }
 
+// dayMetadata returns the metadata of the daily rate file served by the suite except the fetch time.
+func dayMetadata() twfxr.Metadata {
+	sum := sha256.Sum256([]byte(ExchangeRatePage))
+
+	return twfxr.Metadata{
+		QuotedAt:  time.Date(2021, 8, 29, 5, 26, 0, 0, time.FixedZone("UTC+8", 8*60*60)),
+		SourceURL: "https://rate.bot.com.tw/xrt/flcsv/0/day",
+		Filename:  "ExchangeRate@202108290526.csv",
+		Header: http.Header{
+			"Content-Disposition": {` attachment; filename="ExchangeRate@202108290526.csv"`},
+		},
+		SHA256:   hex.EncodeToString(sum[:]),
+		Language: "zh-TW",
+	}
+}
+
 func (suite *twfxrSuite) TearDownSuite() {
 	httpmock.DeactivateAndReset()
 }
@@ -39,14 +76,13 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 	currencies, metadata, err := twfxr.GetCurrencyExchangeRates(context.Background())
 	suite.NoError(err)
 
-	expectedMetadata := twfxr.Metadata{
-		QuotedAt: time.Date(2021, 8, 29, 5, 26, 0, 0, time.FixedZone("UTC+8", 8*60*60)),
-	}
-
-	suite.Equal(expectedMetadata, metadata)
+	suite.False(metadata.FetchedAt.IsZero())
+	metadata.FetchedAt = time.Time{}
+	suite.Equal(dayMetadata(), metadata)
 
 	expectedCurrencies := map[twfxr.Currency]twfxr.CurrencyExchangeRate{
 		twfxr.CurrencyUSD: {
+			Currency:              "USD",
 			BuyingCash:            27.52000,
 			BuyingSpot:            27.84500,
 			BuyingForward10Days:   27.86500,
@@ -67,6 +103,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 27.96700,
 		},
 		twfxr.CurrencyHKD: {
+			Currency:              "HKD",
 			BuyingCash:            3.43000,
 			BuyingSpot:            3.55100,
 			BuyingForward10Days:   3.55400,
@@ -87,6 +124,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 3.61700,
 		},
 		twfxr.CurrencyGBP: {
+			Currency:              "GBP",
 			BuyingCash:            37.26000,
 			BuyingSpot:            38.15500,
 			BuyingForward10Days:   38.11600,
@@ -107,6 +145,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 38.55700,
 		},
 		twfxr.CurrencyAUD: {
+			Currency:              "AUD",
 			BuyingCash:            20.03000,
 			BuyingSpot:            20.24500,
 			BuyingForward10Days:   20.14800,
@@ -127,6 +166,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 20.37800,
 		},
 		twfxr.CurrencyCAD: {
+			Currency:              "CAD",
 			BuyingCash:            21.65000,
 			BuyingSpot:            21.98000,
 			BuyingForward10Days:   21.94700,
@@ -147,6 +187,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 22.16100,
 		},
 		twfxr.CurrencySGD: {
+			Currency:              "SGD",
 			BuyingCash:            20.17000,
 			BuyingSpot:            20.64000,
 			BuyingForward10Days:   20.57700,
@@ -167,6 +208,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 20.76600,
 		},
 		twfxr.CurrencyCHF: {
+			Currency:              "CHF",
 			BuyingCash:            29.82000,
 			BuyingSpot:            30.43000,
 			BuyingForward10Days:   30.32200,
@@ -187,6 +229,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 30.74900,
 		},
 		twfxr.CurrencyJPY: {
+			Currency:              "JPY",
 			BuyingCash:            0.24490,
 			BuyingSpot:            0.25190,
 			BuyingForward10Days:   0.25160,
@@ -207,6 +250,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 0.25630,
 		},
 		twfxr.CurrencyZAR: {
+			Currency:              "ZAR",
 			BuyingCash:            0.00000,
 			BuyingSpot:            1.85100,
 			BuyingForward10Days:   1.83200,
@@ -227,6 +271,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 1.87200,
 		},
 		twfxr.CurrencySEK: {
+			Currency:              "SEK",
 			BuyingCash:            2.85000,
 			BuyingSpot:            3.18000,
 			BuyingForward10Days:   3.16000,
@@ -247,6 +292,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 3.26800,
 		},
 		twfxr.CurrencyNZD: {
+			Currency:              "NZD",
 			BuyingCash:            19.09000,
 			BuyingSpot:            19.42000,
 			BuyingForward10Days:   19.31700,
@@ -267,6 +313,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 19.49700,
 		},
 		twfxr.CurrencyTHB: {
+			Currency:              "THB",
 			BuyingCash:            0.73030,
 			BuyingSpot:            0.83970,
 			BuyingForward10Days:   0.00000,
@@ -287,6 +334,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 0.00000,
 		},
 		twfxr.CurrencyPHP: {
+			Currency:              "PHP",
 			BuyingCash:            0.48640,
 			BuyingSpot:            0.00000,
 			BuyingForward10Days:   0.00000,
@@ -307,6 +355,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 0.00000,
 		},
 		twfxr.CurrencyIDR: {
+			Currency:              "IDR",
 			BuyingCash:            0.00158,
 			BuyingSpot:            0.00000,
 			BuyingForward10Days:   0.00000,
@@ -327,6 +376,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 0.00000,
 		},
 		twfxr.CurrencyEUR: {
+			Currency:              "EUR",
 			BuyingCash:            32.12000,
 			BuyingSpot:            32.63500,
 			BuyingForward10Days:   32.64100,
@@ -347,6 +397,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 33.20800,
 		},
 		twfxr.CurrencyKRW: {
+			Currency:              "KRW",
 			BuyingCash:            0.02229,
 			BuyingSpot:            0.00000,
 			BuyingForward10Days:   0.00000,
@@ -367,6 +418,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 0.00000,
 		},
 		twfxr.CurrencyVND: {
+			Currency:              "VND",
 			BuyingCash:            0.00098,
 			BuyingSpot:            0.00000,
 			BuyingForward10Days:   0.00000,
@@ -387,6 +439,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 0.00000,
 		},
 		twfxr.CurrencyMYR: {
+			Currency:              "MYR",
 			BuyingCash:            5.65200,
 			BuyingSpot:            0.00000,
 			BuyingForward10Days:   0.00000,
@@ -407,6 +460,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRates() {
 			SellingForward180Days: 0.00000,
 		},
 		twfxr.CurrencyCNY: {
+			Currency:              "CNY",
 			BuyingCash:            4.22500,
 			BuyingSpot:            4.29200,
 			BuyingForward10Days:   4.28080,
@@ -457,6 +511,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRate() {
 			},
 			wants: wants{
 				exchangeRate: twfxr.CurrencyExchangeRate{
+					Currency:              "JPY",
 					BuyingCash:            0.24490,
 					BuyingSpot:            0.25190,
 					BuyingForward10Days:   0.25160,
@@ -476,9 +531,7 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRate() {
 					SellingForward150Days: 0.25620,
 					SellingForward180Days: 0.25630,
 				},
-				metadata: twfxr.Metadata{
-					QuotedAt: time.Date(2021, 8, 29, 5, 26, 0, 0, time.FixedZone("UTC+8", 8*60*60)),
-				},
+				metadata: dayMetadata(),
 			},
 		},
 
@@ -500,12 +553,35 @@ func (suite *twfxrSuite) TestGetCurrencyExchangeRate() {
 			}
 
 			assert.NoError(t, err)
+			assert.False(t, metadata.FetchedAt.IsZero())
+			metadata.FetchedAt = time.Time{}
 			assert.Equal(t, tc.wants.metadata, metadata)
 			assert.Equal(t, tc.wants.exchangeRate, exchangeRate)
 		})
 	}
 }
 
-func TesttwfxrSuite(t *testing.T) {
+func (suite *twfxrSuite) TestParseFile() {
+	currencies, metadata, err := twfxr.ParseFile("testdata/ExchangeRate@202108290526.csv")
+	suite.NoError(err)
+
+	expected, _, err := twfxr.GetCurrencyExchangeRates(context.Background())
+	suite.NoError(err)
+
+	suite.Equal(expected, currencies)
+
+	expectedMetadata := dayMetadata()
+	expectedMetadata.SourceURL = ""
+	expectedMetadata.Header = nil
+	suite.Equal(expectedMetadata, metadata)
+
+	_, _, err = twfxr.Parse(strings.NewReader(ExchangeRatePage), "ExchangeRate@latest.csv")
+	suite.Error(err)
+
+	_, _, err = twfxr.ParseFile("testdata/missing.csv")
+	suite.ErrorIs(err, os.ErrNotExist)
+}
+
+func TestTwfxrSuite(t *testing.T) {
 	suite.Run(t, new(twfxrSuite))
 }
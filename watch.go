@@ -0,0 +1,148 @@
+package twfxr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Update is a revision of the board rates found by Watch.
+type Update struct {
+	Metadata Metadata
+	Rates    map[Currency]CurrencyExchangeRate
+	// Changes are the currencies whose cash or spot rates are changed by the revision, sorted by currency. A
+	// currency added or removed by the revision has the zero Old or New respectively.
+	Changes []RateChange
+	// Err is the error of a failed poll, in which case the other fields are empty. Watch keeps polling after an
+	// error.
+	Err error
+}
+
+// RateChange is the change of the rates of a currency between two revisions.
+type RateChange struct {
+	Currency Currency
+	Old      CurrencyExchangeRate
+	New      CurrencyExchangeRate
+}
+
+// WatchOption configures Watch.
+type WatchOption func(*watcher)
+
+// WatchOffHours makes Watch poll around the clock instead of only during Taiwan business hours, when the bank
+// revises the board rates.
+func WatchOffHours() WatchOption {
+	return func(w *watcher) {
+		w.offHours = true
+	}
+}
+
+type watcher struct {
+	offHours bool
+}
+
+// Watch polls the latest board rates at the given interval and sends an Update whenever the bank publishes a new
+// revision, i.e. the quote time changes. The first Update is the rates at the start of watching, which has no changes.
+// Only Taiwan business hours are polled unless WatchOffHours is given. The channel is closed when ctx is done. If the
+// interval is not positive, a single Update with the error is sent before the channel is closed.
+func (c *Client) Watch(ctx context.Context, interval time.Duration, opts ...WatchOption) <-chan Update {
+	var w watcher
+	for _, opt := range opts {
+		opt(&w)
+	}
+
+	updates := make(chan Update)
+
+	go func() {
+		defer close(updates)
+
+		if interval <= 0 {
+			select {
+			case updates <- Update{Err: fmt.Errorf("invalid interval %s", interval)}:
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last *Update
+
+		for {
+			if w.offHours || isBusinessHours(c.now()) {
+				if update, ok := c.poll(ctx, last); ok {
+					select {
+					case updates <- update:
+					case <-ctx.Done():
+						return
+					}
+
+					if update.Err == nil {
+						last = &update
+					}
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates
+}
+
+// Watch is a wrapper of Client.Watch using the default client.
+func Watch(ctx context.Context, interval time.Duration, opts ...WatchOption) <-chan Update {
+	return defaultClient.Watch(ctx, interval, opts...)
+}
+
+// poll fetches the latest board rates and reports whether they are a new revision since the last Update, or a failure
+// to report.
+func (c *Client) poll(ctx context.Context, last *Update) (Update, bool) {
+	currencies, metadata, err := c.GetCurrencyExchangeRates(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Update{}, false
+		}
+		return Update{Err: err}, true
+	}
+
+	if last == nil {
+		return Update{Metadata: metadata, Rates: currencies}, true
+	}
+
+	if metadata.QuotedAt.Equal(last.Metadata.QuotedAt) {
+		return Update{}, false
+	}
+
+	return Update{Metadata: metadata, Rates: currencies, Changes: diffRates(last.Rates, currencies)}, true
+}
+
+// diffRates returns the currencies whose cash or spot rates differ between the two revisions.
+func diffRates(old, new map[Currency]CurrencyExchangeRate) []RateChange {
+	var changes []RateChange
+
+	for currency, n := range new {
+		o, ok := old[currency]
+		if !ok || o.BuyingCash != n.BuyingCash || o.BuyingSpot != n.BuyingSpot ||
+			o.SellingCash != n.SellingCash || o.SellingSpot != n.SellingSpot {
+			changes = append(changes, RateChange{Currency: currency, Old: o, New: n})
+		}
+	}
+
+	for currency, o := range old {
+		if _, ok := new[currency]; !ok {
+			changes = append(changes, RateChange{Currency: currency, Old: o})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Currency < changes[j].Currency
+	})
+
+	return changes
+}
@@ -0,0 +1,160 @@
+package twfxr_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mkfsn/twfxr"
+	"github.com/stretchr/testify/suite"
+)
+
+type watchSuite struct {
+	suite.Suite
+
+	transport *httpmock.MockTransport
+}
+
+func (suite *watchSuite) SetupTest() {
+	revised := strings.Replace(ExchangeRatePage, "USD,本行買入,27.52000,27.84500", "USD,本行買入,27.50000,27.82500", 1)
+
+	responses := []struct {
+		filename string
+		body     string
+	}{
+		{filename: "ExchangeRate@202108290526.csv", body: ExchangeRatePage},
+		{filename: "ExchangeRate@202108290526.csv", body: ExchangeRatePage},
+		{filename: "ExchangeRate@202108290930.csv", body: revised},
+	}
+
+	calls := 0
+
+	suite.transport = httpmock.NewMockTransport()
+	suite.transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/day",
+		func(req *http.Request) (*http.Response, error) {
+			r := responses[len(responses)-1]
+			if calls < len(responses) {
+				r = responses[calls]
+			}
+			calls++
+
+			resp := newResponse(http.StatusOK, r.body)
+			resp.Header.Add("Content-Disposition", `attachment; filename="`+r.filename+`"`)
+			return resp, nil
+		},
+	)
+}
+
+func (suite *watchSuite) newClient(now time.Time) *twfxr.Client {
+	return twfxr.NewClient(
+		twfxr.WithHTTPClient(&http.Client{Transport: suite.transport}),
+		twfxr.WithClock(func() time.Time { return now }),
+	)
+}
+
+func (suite *watchSuite) TestWatch() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Sunday
+	updates := suite.newClient(time.Date(2021, 8, 29, 5, 30, 0, 0, time.UTC)).Watch(ctx, time.Millisecond, twfxr.WatchOffHours())
+
+	update := <-updates
+	suite.NoError(update.Err)
+	suite.Equal(time.Date(2021, 8, 29, 5, 26, 0, 0, time.FixedZone("UTC+8", 8*60*60)), update.Metadata.QuotedAt)
+	suite.Len(update.Rates, 19)
+	suite.Empty(update.Changes)
+
+	update = <-updates
+	suite.NoError(update.Err)
+	suite.Equal(time.Date(2021, 8, 29, 9, 30, 0, 0, time.FixedZone("UTC+8", 8*60*60)), update.Metadata.QuotedAt)
+	suite.Len(update.Rates, 19)
+	if suite.Len(update.Changes, 1) {
+		change := update.Changes[0]
+		suite.Equal(twfxr.CurrencyUSD, change.Currency)
+		suite.Equal(twfxr.Rate(27.52), change.Old.BuyingCash)
+		suite.Equal(twfxr.Rate(27.5), change.New.BuyingCash)
+		suite.Equal(twfxr.Rate(27.845), change.Old.BuyingSpot)
+		suite.Equal(twfxr.Rate(27.825), change.New.BuyingSpot)
+	}
+	suite.GreaterOrEqual(suite.transport.GetTotalCallCount(), 3)
+
+	cancel()
+	for range updates {
+	}
+}
+
+func (suite *watchSuite) TestWatchOnlyBusinessHours() {
+	testCases := map[string]struct {
+		now   time.Time
+		calls bool
+	}{
+		"When it is a weekday morning, Then it polls": {
+			now:   time.Date(2021, 8, 27, 10, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60)),
+			calls: true,
+		},
+		"When it is a weekday night, Then it does not poll": {
+			now:   time.Date(2021, 8, 27, 20, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60)),
+			calls: false,
+		},
+		"When it is a Sunday, Then it does not poll": {
+			now:   time.Date(2021, 8, 29, 10, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60)),
+			calls: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			suite.SetupTest()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			n := 0
+			for range suite.newClient(tc.now).Watch(ctx, time.Millisecond) {
+				n++
+			}
+
+			suite.Equal(tc.calls, n > 0)
+			suite.Equal(tc.calls, suite.transport.GetTotalCallCount() > 0)
+		})
+	}
+}
+
+func (suite *watchSuite) TestWatchInvalidInterval() {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		var updates []twfxr.Update
+		for update := range suite.newClient(time.Now()).Watch(context.Background(), interval, twfxr.WatchOffHours()) {
+			updates = append(updates, update)
+		}
+
+		if suite.Len(updates, 1) {
+			suite.EqualError(updates[0].Err, "invalid interval "+interval.String())
+		}
+		suite.Zero(suite.transport.GetTotalCallCount())
+	}
+}
+
+func (suite *watchSuite) TestWatchError() {
+	suite.transport.RegisterResponder(http.MethodGet, "https://rate.bot.com.tw/xrt/flcsv/0/day",
+		httpmock.NewStringResponder(http.StatusForbidden, ""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := suite.newClient(time.Now()).Watch(ctx, time.Millisecond, twfxr.WatchOffHours())
+
+	suite.Error((<-updates).Err)
+	suite.Error((<-updates).Err)
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestWatchSuite(t *testing.T) {
+	suite.Run(t, new(watchSuite))
+}